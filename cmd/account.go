@@ -4,173 +4,78 @@ Copyright © 2025 Hyperbolic Labs
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
 
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
 	"github.com/spf13/cobra"
 )
 
-// Balance response structure
-type BalanceResponse struct {
-	Credits int `json:"credits"`
-}
-
-type UserResponse struct {
-	Email           string        `json:"email"`
-	Picture         interface{}   `json:"picture"`
-	Provider        string        `json:"provider"`
-	EmailVerified   bool          `json:"email_verified"`
-	Name            string        `json:"name"`
-	PublicKey       string        `json:"public_key"`
-	OnboardedAt     time.Time     `json:"onboarded_at"`
-	OnboardedFor    string        `json:"onboarded_for"`
-	Meta            interface{}   `json:"meta"`
-	ReferralCode    string        `json:"referral_code"`
-	ID              string        `json:"id"`
-	IsActive        bool          `json:"is_active"`
-	APIKey          string        `json:"api_key"`
-	Role            string        `json:"role"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at"`
-	CompletedPromos []interface{} `json:"completed_promos"`
-	Roles           []interface{} `json:"roles"`
-}
-
 // accountCmd represents the account command
 var accountCmd = &cobra.Command{
 	Use:   "account",
 	Short: "View your account information and balance.",
 	Long:  `View your Hyperbolic account information and balance.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		jsonFormat, _ := cmd.Flags().GetBool("json")
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := selectedOutput(cmd)
+		if err != nil {
+			return err
+		}
 
-		// Get API key from config file
-		apiKey, err := GetAPIKey()
+		client, err := newClient()
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			fmt.Printf("Please run 'hyperbolic auth YOUR_API_KEY' to save your API key\n")
-			fmt.Printf("(Get your API key from https://app.hyperbolic.ai/settings)\n")
-			return
+			return err
 		}
 
 		// Fetch user information
-		user, err := fetchUser(apiKey)
+		user, err := client.GetUser(cmd.Context())
 		if err != nil {
-			fmt.Printf("Error: failed to fetch user information: %v\n", err)
-			return
+			return fmt.Errorf("failed to fetch user information: %w", err)
 		}
 
 		// Fetch balance
-		balance, err := fetchBalance(apiKey)
+		balance, err := client.GetBalance(cmd.Context())
 		if err != nil {
-			fmt.Printf("Error: failed to fetch balance: %v\n", err)
-			return
+			return fmt.Errorf("failed to fetch balance: %w", err)
 		}
 
-		if jsonFormat {
-			// Output as JSON
-			accountInfo := map[string]interface{}{
-				"user":    user,
-				"balance": balance,
-			}
-			accountJSON, err := json.MarshalIndent(accountInfo, "", "  ")
-			if err != nil {
-				fmt.Printf("Error formatting JSON: %v\n", err)
-				return
-			}
-			fmt.Println(string(accountJSON))
-		} else {
-			// Display formatted account info
-			printAccountInfo(user, balance)
+		if !format.IsTable() {
+			return printStructured(format, newAccountOutput(user, balance), accountTable(user, balance))
 		}
-	},
-}
-
-func fetchUser(apiKey string) (UserResponse, error) {
-	url := "https://api.hyperbolic.xyz/users/me"
-	
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return UserResponse{}, err
-	}
 
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return UserResponse{}, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return UserResponse{}, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return UserResponse{}, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var userResponse UserResponse
-	err = json.Unmarshal(body, &userResponse)
-	if err != nil {
-		return UserResponse{}, fmt.Errorf("failed to parse user response: %v", err)
-	}
-
-	return userResponse, nil
-}
-
-func fetchBalance(apiKey string) (BalanceResponse, error) {
-	url := "https://api.hyperbolic.xyz/billing/get_current_balance"
-	
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return BalanceResponse{}, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return BalanceResponse{}, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return BalanceResponse{}, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return BalanceResponse{}, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var balanceResponse BalanceResponse
-	err = json.Unmarshal(body, &balanceResponse)
-	if err != nil {
-		return BalanceResponse{}, fmt.Errorf("failed to parse balance response: %v", err)
-	}
-
-	return balanceResponse, nil
+		printAccountInfo(user, balance, format.Wide())
+		return nil
+	},
 }
 
-func printAccountInfo(user UserResponse, balance BalanceResponse) {
+func printAccountInfo(user hyperbolic.UserResponse, balance hyperbolic.BalanceResponse, wide bool) {
 	// Print email first
 	fmt.Printf("Email: %s\n", user.Email)
-	
+
 	// Convert credits (stored in cents) to dollars
 	dollars := float64(balance.Credits) / 100.0
 	fmt.Printf("Balance: $%.2f\n", dollars)
+
+	if wide {
+		fmt.Printf("Name: %s\n", user.Name)
+		fmt.Printf("User ID: %s\n", user.ID)
+	}
+}
+
+// accountTable returns the account as a single row for csv and tsv output
+func accountTable(user hyperbolic.UserResponse, balance hyperbolic.BalanceResponse) *printer.Table {
+	table := printer.NewTable(
+		printer.Column{Header: "EMAIL"},
+		printer.Column{Header: "BALANCE"},
+		printer.Column{Header: "NAME", Wide: true},
+		printer.Column{Header: "USER ID", Wide: true},
+	)
+	table.Append(user.Email, fmt.Sprintf("%.2f", float64(balance.Credits)/100.0), user.Name, user.ID)
+	return table
 }
 
 func init() {
 	rootCmd.AddCommand(accountCmd)
-	accountCmd.Flags().Bool("json", false, "Output raw JSON response")
-} 
\ No newline at end of file
+	accountCmd.Flags().Bool("json", false, "Output as JSON (same as -o json)")
+}
@@ -0,0 +1,653 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Actions of a rental plan
+const (
+	planActionCreate    = "create"
+	planActionKeep      = "keep"
+	planActionTerminate = "terminate"
+)
+
+// specFileHelp describes the spec file in the help of plan and apply
+const specFileHelp = `The spec file, in YAML or JSON, lists the rentals you want:
+
+  name: training             # optional, defaults to the file name
+  rentals:
+    - name: workers
+      type: spot             # spot, virtual-machine or bare-metal
+      count: 2               # default 1
+      gpuCount: 1            # GPUs per rental, default 1
+      gpuModel: "4090"       # spot only, like 'hyperbolic spot --gpu-model'
+      maxPrice: 0.5          # spot only, per GPU per hour in USD
+      region: eu-central     # spot only, default any region
+      ports: [8080]          # spot only, up to 2
+    - name: trainer
+      type: bare-metal
+      gpuCount: 16           # a multiple of 8
+      networkType: infiniband
+
+Rentals made by apply are recorded as managed for the spec's name in ~/.hyperbolic/apply/<profile>/<name>.json; other rentals are never changed. Managed rentals that end, such as spot rentals that are reclaimed, are replaced on the next apply. Managed rentals beyond the count, or that no longer match or are no longer in the spec, are only terminated with --prune.`
+
+// planCmd represents the plan command
+var planCmd = &cobra.Command{
+	Use:   "plan -f <spec-file>",
+	Short: "Show the changes 'apply' would make for a rental spec.",
+	Long: `Compare a rental spec with your instances and show the rentals 'hyperbolic apply' would create, keep and terminate, with where each spot rental would be placed and the change in hourly cost. Nothing is rented or terminated.
+
+` + specFileHelp + `
+
+EXAMPLES:
+  hyperbolic plan -f rentals.yaml
+  hyperbolic plan -f rentals.yaml --prune -o json | jq .costDeltaPerHourUSD`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := selectedOutput(cmd)
+		if err != nil {
+			return err
+		}
+
+		plan, _, err := loadRentalPlan(cmd)
+		if err != nil {
+			return err
+		}
+
+		table := rentalPlanTableBuilder.Table(plan.Actions)
+		if !format.IsTable() {
+			return printStructured(format, plan, table)
+		}
+		printRentalPlan(os.Stdout, plan, table, format.Wide())
+		return nil
+	},
+}
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <spec-file>",
+	Short: "Rent or terminate instances to match a rental spec.",
+	Long: `Reconcile your rentals with a rental spec: show the plan, as 'hyperbolic plan' does, then rent what is missing through the spot and on-demand marketplaces and, with --prune, terminate managed rentals the spec no longer wants.
+
+Nothing is changed if any rental cannot be placed, for example because no spot node has enough GPUs available under maxPrice. Rentals are created before any are terminated, and the record of managed rentals is saved after each change, so running apply again after a failure carries on where it stopped. If the API does not return a new rental's ID and it cannot be told apart from other new instances, apply stops without managing it; check 'hyperbolic instances' before running apply again.
+
+` + specFileHelp + `
+
+EXAMPLES:
+  hyperbolic apply -f rentals.yaml
+  hyperbolic apply -f rentals.yaml --prune --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+		if outputFormat != "" {
+			return usageErrorf("apply does not support -o; use 'hyperbolic plan -o %s' for a machine-readable plan", outputFormat)
+		}
+
+		plan, run, err := loadRentalPlan(cmd)
+		if err != nil {
+			return err
+		}
+		printRentalPlan(os.Stdout, plan, rentalPlanTableBuilder.Table(plan.Actions), false)
+
+		var unplaced, changes int
+		for _, action := range plan.Actions {
+			if action.Error != "" {
+				unplaced++
+			}
+			if action.Action != planActionKeep {
+				changes++
+			}
+		}
+		if unplaced > 0 {
+			return fmt.Errorf("nothing was changed: %d rental(s) cannot be created now", unplaced)
+		}
+
+		// Forget managed rentals that have ended, which the plan replaces
+		if err := run.state.save(run.statePath); err != nil {
+			return err
+		}
+		if changes == 0 {
+			return nil
+		}
+
+		if !yes {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return usageErrorf("apply needs --yes to make changes when not run interactively")
+			}
+			fmt.Print("\nApply these changes? Only 'yes' will be accepted: ")
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if strings.TrimSpace(answer) != "yes" {
+				fmt.Println("Apply cancelled.")
+				return nil
+			}
+		}
+		fmt.Println()
+		return run.apply(cmd.Context(), plan)
+	},
+}
+
+// rentalPlanRun holds what apply needs to carry out a plan
+type rentalPlanRun struct {
+	client    *hyperbolic.Client
+	spec      rentalSpecFile
+	state     applyState
+	statePath string
+	// instances are the caller's instances when the plan was made, by ID
+	instances map[string]hyperbolic.Instance
+}
+
+// loadRentalPlan reads the spec file given with --file and plans the
+// changes to make for it
+func loadRentalPlan(cmd *cobra.Command) (rentalPlanOutput, *rentalPlanRun, error) {
+	path, _ := cmd.Flags().GetString("file")
+	prune, _ := cmd.Flags().GetBool("prune")
+
+	spec, err := readRentalSpecFile(path)
+	if err != nil {
+		return rentalPlanOutput{}, nil, err
+	}
+
+	statePath, err := applyStatePath(spec.Name)
+	if err != nil {
+		return rentalPlanOutput{}, nil, err
+	}
+	state, err := loadApplyState(statePath)
+	if err != nil {
+		return rentalPlanOutput{}, nil, err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return rentalPlanOutput{}, nil, err
+	}
+
+	instances, err := client.ListInstances(cmd.Context())
+	if err != nil {
+		return rentalPlanOutput{}, nil, fmt.Errorf("error fetching instances: %w", err)
+	}
+
+	run := &rentalPlanRun{
+		client:    client,
+		spec:      spec,
+		state:     state,
+		statePath: statePath,
+		instances: map[string]hyperbolic.Instance{},
+	}
+	for _, instance := range instances {
+		run.instances[instance.ID] = instance
+	}
+
+	plan, err := run.plan(cmd.Context(), prune)
+	return plan, run, err
+}
+
+// plan compares the spec with the managed instances that have not ended,
+// which it leaves in the state, and decides where new rentals go
+func (r *rentalPlanRun) plan(ctx context.Context, prune bool) (rentalPlanOutput, error) {
+	plan := rentalPlanOutput{
+		typeMeta: newTypeMeta(kindRentalPlan),
+		Name:     r.spec.Name,
+		Actions:  []rentalPlanActionOutput{},
+	}
+
+	for name, ids := range r.state.Rentals {
+		var live []string
+		for _, id := range ids {
+			if instance, ok := r.instances[id]; ok && !instance.HasEnded() {
+				live = append(live, id)
+			}
+		}
+		r.state.Rentals[name] = live
+	}
+
+	// Managed instances the spec does not want
+	var extras []rentalPlanActionOutput
+	placer := &rentalPlacer{reserved: map[string]int{}}
+	wanted := map[string]bool{}
+	for _, rental := range r.spec.Rentals {
+		wanted[rental.Name] = true
+
+		kept := 0
+		for _, id := range r.state.Rentals[rental.Name] {
+			instance := r.instances[id]
+			switch {
+			case !rental.matches(instance):
+				extras = append(extras, instanceAction(rental.Name, instance, "no longer matches the spec"))
+			case kept >= rental.count():
+				extras = append(extras, instanceAction(rental.Name, instance, fmt.Sprintf("more than the %d wanted", rental.count())))
+			default:
+				kept++
+				plan.Actions = append(plan.Actions, instanceAction(rental.Name, instance, ""))
+			}
+		}
+
+		for ; kept < rental.count(); kept++ {
+			action, err := placer.place(ctx, r.client, rental)
+			if err != nil {
+				return plan, err
+			}
+			plan.Actions = append(plan.Actions, action)
+		}
+	}
+
+	var removed []string
+	for name := range r.state.Rentals {
+		if !wanted[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		for _, id := range r.state.Rentals[name] {
+			extras = append(extras, instanceAction(name, r.instances[id], "not in the spec"))
+		}
+	}
+
+	for _, action := range extras {
+		if prune {
+			action.Action = planActionTerminate
+		} else {
+			action.Reason += "; --prune terminates it"
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+
+	for _, action := range plan.Actions {
+		switch {
+		case action.Action == planActionCreate && action.Error == "":
+			plan.PlannedCostPerHourUSD += action.PricePerHourUSD
+		case action.Action == planActionKeep:
+			plan.CurrentCostPerHourUSD += action.PricePerHourUSD
+			plan.PlannedCostPerHourUSD += action.PricePerHourUSD
+		case action.Action == planActionTerminate:
+			plan.CurrentCostPerHourUSD += action.PricePerHourUSD
+		}
+	}
+	plan.CostDeltaPerHourUSD = plan.PlannedCostPerHourUSD - plan.CurrentCostPerHourUSD
+	return plan, nil
+}
+
+// instanceAction returns a keep action for an existing instance, with the
+// reason it is not wanted, if any
+func instanceAction(rental string, instance hyperbolic.Instance, reason string) rentalPlanActionOutput {
+	return rentalPlanActionOutput{
+		Action:          planActionKeep,
+		Rental:          rental,
+		Type:            string(instance.Type),
+		InstanceID:      instance.ID,
+		GPUModel:        displayGPUModel(instance.GPUModel),
+		GPUCount:        instance.GPUCount,
+		NetworkType:     instance.NetworkType,
+		PricePerHourUSD: instance.CostPerHour,
+		Reason:          reason,
+	}
+}
+
+// rentalPlacer decides where the rentals of a plan go, fetching the spot
+// marketplace and on-demand options the first time they are needed
+type rentalPlacer struct {
+	market           []hyperbolic.MarketplaceInstance
+	vmOptions        hyperbolic.VirtualMachineOptions
+	bareMetalOptions hyperbolic.BareMetalOptions
+	fetchedMarket    bool
+	fetchedOnDemand  bool
+	// reserved counts the GPUs taken by earlier rentals of the plan, by spot
+	// node ID or bare-metal network type
+	reserved map[string]int
+}
+
+// place returns the create action for a rental, with an error message if
+// nothing available fits it
+func (p *rentalPlacer) place(ctx context.Context, client *hyperbolic.Client, rental rentalSpec) (rentalPlanActionOutput, error) {
+	action := rentalPlanActionOutput{
+		Action:      planActionCreate,
+		Rental:      rental.Name,
+		Type:        rental.Type,
+		GPUCount:    rental.GPUCount,
+		NetworkType: rental.NetworkType,
+	}
+
+	if rental.Type == string(hyperbolic.InstanceTypeSpot) {
+		if !p.fetchedMarket {
+			market, err := client.ListMarketplace(ctx, nil)
+			if err != nil {
+				return action, fmt.Errorf("error fetching spot marketplace: %w", err)
+			}
+			p.market = market.Instances
+			p.fetchedMarket = true
+		}
+
+		// The spec was checked, so the price is at least a cent
+		maxPrice, _ := maxPriceCents(rental.MaxPrice)
+		filter := hyperbolic.MarketplaceFilter{
+			GPUModel: rental.GPUModel,
+			Region:   rental.Region,
+			Cluster:  rental.Cluster,
+			MaxPrice: maxPrice,
+		}
+		for _, node := range filterMarketplaceInstances(p.market, filter, false) {
+			if node.AvailableGPUs()-p.reserved[node.ID] < rental.GPUCount {
+				continue
+			}
+			p.reserved[node.ID] += rental.GPUCount
+			action.GPUModel = displayGPUModel(node.GPU().Model)
+			action.Cluster = node.ClusterName
+			action.Node = node.ID
+			action.Region = node.Location.Region
+			action.PricePerHourUSD = float64(node.Pricing.Price.Amount) / 100 * float64(rental.GPUCount)
+			return action, nil
+		}
+		action.GPUModel = rental.GPUModel
+		action.Error = fmt.Sprintf("no spot node matching the spec has %d GPU(s) available", rental.GPUCount)
+		return action, nil
+	}
+
+	if !p.fetchedOnDemand {
+		vmOptions, bareMetalOptions, err := fetchOnDemandOptions(ctx)
+		if err != nil {
+			return action, fmt.Errorf("error fetching on-demand options: %w", err)
+		}
+		p.vmOptions, p.bareMetalOptions = vmOptions, bareMetalOptions
+		p.fetchedOnDemand = true
+	}
+	action.GPUModel = displayGPUModel(onDemandGPUModel)
+
+	if rental.Type == string(hyperbolic.InstanceTypeVirtualMachine) {
+		var counts []string
+		for _, option := range p.vmOptions {
+			if option.GPUCount == rental.GPUCount {
+				action.PricePerHourUSD = option.CostPerHour * float64(rental.GPUCount)
+				return action, nil
+			}
+			counts = append(counts, strconv.Itoa(option.GPUCount))
+		}
+		action.Error = fmt.Sprintf("no virtual machine has %d GPU(s); available sizes: %s", rental.GPUCount, strings.Join(counts, ", "))
+		return action, nil
+	}
+
+	option := p.bareMetalOptions.Ethernet
+	if rental.NetworkType == "infiniband" {
+		option = p.bareMetalOptions.Infiniband
+	}
+	if available := option.GPUCount - p.reserved[rental.NetworkType]; available < rental.GPUCount {
+		action.Error = fmt.Sprintf("only %d %s bare-metal GPU(s) available", max(available, 0), rental.NetworkType)
+		return action, nil
+	}
+	p.reserved[rental.NetworkType] += rental.GPUCount
+	action.PricePerHourUSD = option.CostPerHour * float64(rental.GPUCount)
+	return action, nil
+}
+
+// apply carries out the plan's creates, then its terminations, saving the
+// state after each
+func (r *rentalPlanRun) apply(ctx context.Context, plan rentalPlanOutput) error {
+	specs := map[string]rentalSpec{}
+	for _, rental := range r.spec.Rentals {
+		specs[rental.Name] = rental
+	}
+
+	created, terminated := 0, 0
+	for _, action := range plan.Actions {
+		if action.Action != planActionCreate {
+			continue
+		}
+		id, err := r.create(ctx, specs[action.Rental], action)
+		if err != nil {
+			return fmt.Errorf("error creating rental '%s': %w", action.Rental, err)
+		}
+		created++
+		r.state.Rentals[action.Rental] = append(r.state.Rentals[action.Rental], id)
+		if err := r.state.save(r.statePath); err != nil {
+			return err
+		}
+		fmt.Printf("Created %s rental '%s': instance %s\n", instanceTypeLabel(hyperbolic.InstanceType(action.Type)), action.Rental, id)
+	}
+
+	for _, action := range plan.Actions {
+		if action.Action != planActionTerminate {
+			continue
+		}
+		instance := r.instances[action.InstanceID]
+		if err := r.client.TerminateInstance(ctx, instance); err != nil {
+			return fmt.Errorf("error terminating instance %s: %w", instance.ID, err)
+		}
+		terminated++
+		r.state.remove(instance.ID)
+		if err := r.state.save(r.statePath); err != nil {
+			return err
+		}
+		fmt.Printf("Terminated %s instance %s of rental '%s'\n", instanceTypeLabel(instance.Type), instance.ID, action.Rental)
+	}
+
+	fmt.Printf("\nApply complete: %d created, %d terminated.\n", created, terminated)
+	if created > 0 {
+		fmt.Println("To view the status and get the SSH commands, run:")
+		fmt.Println("  hyperbolic instances")
+	}
+	return nil
+}
+
+// create rents the instance of a create action and returns its ID. Create
+// responses do not always include the ID, in which case it is looked up as
+// the one instance that appeared since just before the create call and
+// matches the action, as it is when the call fails in a way that could come
+// after the rental was made. If that is not clear an error is returned rather
+// than risk managing someone else's rental.
+func (r *rentalPlanRun) create(ctx context.Context, rental rentalSpec, action rentalPlanActionOutput) (string, error) {
+	before, err := r.client.ListInstances(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error fetching instances: %w", err)
+	}
+
+	var id string
+	switch hyperbolic.InstanceType(rental.Type) {
+	case hyperbolic.InstanceTypeSpot:
+		request := hyperbolic.RentRequest{
+			ClusterName: action.Cluster,
+			NodeName:    action.Node,
+			GpuCount:    rental.GPUCount,
+		}
+		if len(rental.Ports) > 0 {
+			request.Image = &hyperbolic.Image{Name: hyperbolic.SpotImage, Ports: rental.Ports}
+		}
+		response, err := r.client.RentSpotInstance(ctx, request)
+		if err != nil {
+			return r.findFailedCreate(ctx, before, rental, action, err)
+		}
+		id = response.InstanceID
+	case hyperbolic.InstanceTypeVirtualMachine, hyperbolic.InstanceTypeBareMetal:
+		var response hyperbolic.OnDemandRentResponse
+		var err error
+		if rental.Type == string(hyperbolic.InstanceTypeVirtualMachine) {
+			response, err = r.client.RentVirtualMachine(ctx, rental.GPUCount)
+		} else {
+			response, err = r.client.RentBareMetal(ctx, rental.GPUCount, rental.NetworkType)
+		}
+		if err != nil {
+			return r.findFailedCreate(ctx, before, rental, action, err)
+		}
+		if response.ID != 0 {
+			id = strconv.Itoa(response.ID)
+		}
+	}
+
+	if id == "" {
+		if id, err = r.findCreated(ctx, before, rental, action); err != nil {
+			return "", err
+		}
+	}
+	r.instances[id] = hyperbolic.Instance{ID: id}
+	return id, nil
+}
+
+// findCreated returns the ID of the instance made by a create call whose
+// response had none: the only instance not in before that matches the
+// action. Another rental made in the meantime, such as by hand, could
+// otherwise be taken for it.
+func (r *rentalPlanRun) findCreated(ctx context.Context, before []hyperbolic.Instance, rental rentalSpec, action rentalPlanActionOutput) (string, error) {
+	created, err := r.newInstances(ctx, before, rental, action)
+	if err != nil {
+		return "", fmt.Errorf("the rental was made, but its ID could not be looked up, so it is not managed by apply; check 'hyperbolic instances' before applying again: %w", err)
+	}
+	if len(created) != 1 {
+		return "", fmt.Errorf("the rental was made, but its ID was not returned and %d new instance(s) match it, so it is not managed by apply; check 'hyperbolic instances' before applying again", len(created))
+	}
+	return created[0], nil
+}
+
+// findFailedCreate returns the ID of the instance made by a create call that
+// failed with createErr. A server or network error can come after the rental
+// was made, so it is looked up as in findCreated; if there is not exactly one
+// match the error says to check for it by hand.
+func (r *rentalPlanRun) findFailedCreate(ctx context.Context, before []hyperbolic.Instance, rental rentalSpec, action rentalPlanActionOutput, createErr error) (string, error) {
+	var apiErr *hyperbolic.APIError
+	var networkErr *hyperbolic.NetworkError
+	if !(errors.As(createErr, &apiErr) && apiErr.IsServerError()) && !errors.As(createErr, &networkErr) {
+		return "", rentError(createErr)
+	}
+
+	created, err := r.newInstances(ctx, before, rental, action)
+	if err != nil || len(created) != 1 {
+		return "", fmt.Errorf("%w\nThe rental may have been made anyway; check 'hyperbolic instances' before applying again", rentError(createErr))
+	}
+	fmt.Fprintf(os.Stderr, "Note: the rental request failed (%v), but instance %s was made for rental '%s'\n", createErr, created[0], action.Rental)
+	r.instances[created[0]] = hyperbolic.Instance{ID: created[0]}
+	return created[0], nil
+}
+
+// newInstances returns the IDs of the instances that are not in before, have
+// not ended and match the action
+func (r *rentalPlanRun) newInstances(ctx context.Context, before []hyperbolic.Instance, rental rentalSpec, action rentalPlanActionOutput) ([]string, error) {
+	instances, err := r.client.ListInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, instance := range before {
+		known[instance.ID] = true
+	}
+
+	var created []string
+	for _, instance := range instances {
+		if known[instance.ID] || instance.HasEnded() || !rental.matches(instance) {
+			continue
+		}
+		if action.Node != "" && (instance.Spot == nil || instance.Spot.Instance.ID != action.Node) {
+			continue
+		}
+		created = append(created, instance.ID)
+	}
+	return created, nil
+}
+
+// rentalPlanTableBuilder describes the columns of 'hyperbolic plan'
+var rentalPlanTableBuilder = printer.TableBuilder[rentalPlanActionOutput]{
+	Fields: []printer.Field[rentalPlanActionOutput]{
+		{Name: "action", Header: "ACTION", Value: func(action rentalPlanActionOutput) string {
+			return action.Action
+		}},
+		{Name: "rental", Header: "RENTAL", Value: func(action rentalPlanActionOutput) string {
+			return action.Rental
+		}},
+		{Name: "type", Header: "TYPE", Value: func(action rentalPlanActionOutput) string {
+			return instanceTypeLabel(hyperbolic.InstanceType(action.Type))
+		}},
+		{Name: "instance", Header: "INSTANCE", Value: func(action rentalPlanActionOutput) string {
+			if action.InstanceID == "" {
+				return "-"
+			}
+			return action.InstanceID
+		}},
+		{Name: "gpus", Header: "GPUS", Value: func(action rentalPlanActionOutput) string {
+			model := action.GPUModel
+			if model == "" {
+				model = "any GPU"
+			}
+			return fmt.Sprintf("%d× %s", action.GPUCount, model)
+		}},
+		{Name: "placement", Header: "PLACEMENT", Value: func(action rentalPlanActionOutput) string {
+			switch {
+			case action.Node != "":
+				return fmt.Sprintf("%s/%s (%s)", action.Cluster, action.Node, action.Region)
+			case action.NetworkType != "":
+				return action.NetworkType
+			}
+			return "-"
+		}},
+		{Name: "price", Header: "PRICE/HR", Value: func(action rentalPlanActionOutput) string {
+			switch {
+			case action.Error != "":
+				return "N/A"
+			case action.Action == planActionCreate:
+				return fmt.Sprintf("+$%.2f", action.PricePerHourUSD)
+			case action.Action == planActionTerminate:
+				return fmt.Sprintf("-$%.2f", action.PricePerHourUSD)
+			}
+			return fmt.Sprintf("$%.2f", action.PricePerHourUSD)
+		}},
+		{Name: "note", Header: "NOTE", Value: func(action rentalPlanActionOutput) string {
+			if action.Error != "" {
+				return "cannot create: " + action.Error
+			}
+			return action.Reason
+		}},
+	},
+}
+
+// printRentalPlan writes the plan's table and a summary of its changes
+func printRentalPlan(w io.Writer, plan rentalPlanOutput, table *printer.Table, wide bool) {
+	counts := map[string]int{}
+	unplaced := 0
+	for _, action := range plan.Actions {
+		counts[action.Action]++
+		if action.Error != "" {
+			unplaced++
+		}
+	}
+
+	if len(plan.Actions) == 0 {
+		fmt.Fprintf(w, "The spec '%s' has no rentals and none are managed for it.\n", plan.Name)
+		return
+	}
+	table.Render(w, wide)
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Plan for '%s': %d to create, %d to keep, %d to terminate.\n", plan.Name, counts[planActionCreate], counts[planActionKeep], counts[planActionTerminate])
+	sign := "+"
+	if plan.CostDeltaPerHourUSD < 0 {
+		sign = "-"
+	}
+	fmt.Fprintf(w, "Hourly cost: $%.2f → $%.2f (%s$%.2f/hr)\n", plan.CurrentCostPerHourUSD, plan.PlannedCostPerHourUSD, sign, math.Abs(plan.CostDeltaPerHourUSD))
+	if unplaced > 0 {
+		fmt.Fprintf(w, "%d rental(s) cannot be created now, so apply would make no changes.\n", unplaced)
+	} else if counts[planActionCreate]+counts[planActionTerminate] == 0 {
+		fmt.Fprintln(w, "No changes: your rentals match the spec.")
+	}
+}
+
+func init() {
+	for _, command := range []*cobra.Command{planCmd, applyCmd} {
+		rootCmd.AddCommand(command)
+		command.Flags().StringP("file", "f", "", "Rental spec file in YAML or JSON, or '-' for standard input (required)")
+		command.Flags().Bool("prune", false, "Terminate managed rentals the spec no longer wants")
+		command.MarkFlagRequired("file")
+	}
+	applyCmd.Flags().BoolP("yes", "y", false, "Apply the plan without asking for confirmation")
+}
@@ -0,0 +1,214 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic/mock"
+)
+
+// Routes of the mock API that tests intercept
+const (
+	routeRentSpot      = "POST /v1/marketplace/instances/create"
+	routeListInstances = "GET /v1/marketplace/instances"
+)
+
+// newInterceptClient returns a client of the mock API whose requests go
+// through intercept, which can pass them on to the mock, change the mock's
+// response or answer them itself. Failed requests are not retried.
+func newInterceptClient(t *testing.T, intercept func(w http.ResponseWriter, r *http.Request, backend http.Handler)) *hyperbolic.Client {
+	t.Helper()
+	backend := mock.NewServer()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		intercept(w, r, backend)
+	}))
+	t.Cleanup(server.Close)
+	return hyperbolic.NewClient("test-key", hyperbolic.WithBaseURL(server.URL), hyperbolic.WithRetryPolicy(hyperbolic.RetryPolicy{}))
+}
+
+// rentOnMock rents a spot instance on a node of the mock directly, as
+// someone renting by hand would, and returns its ID
+func rentOnMock(t *testing.T, backend http.Handler, node string) string {
+	t.Helper()
+	body, _ := json.Marshal(hyperbolic.RentRequest{ClusterName: "mock-cluster-east", NodeName: node, GpuCount: 1})
+	request := httptest.NewRequest(http.MethodPost, "/v1/marketplace/instances/create", strings.NewReader(string(body)))
+	request.Header.Set("Authorization", "Bearer test-key")
+	recorder := httptest.NewRecorder()
+	backend.ServeHTTP(recorder, request)
+	var response hyperbolic.SpotRentResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.InstanceID == "" {
+		t.Fatalf("renting on the mock: %s", recorder.Body.String())
+	}
+	return response.InstanceID
+}
+
+func TestRentalPlanRunCreateWithoutID(t *testing.T) {
+	rental := rentalSpec{Name: "trainer", Type: string(hyperbolic.InstanceTypeSpot), GPUCount: 1}
+	action := rentalPlanActionOutput{Action: planActionCreate, Rental: "trainer", Type: rental.Type, GPUCount: 1, Cluster: "mock-cluster-east", Node: "mock-h100-1"}
+
+	tests := []struct {
+		name string
+		// manual is set to rent another matching instance by hand before
+		// or during the create call
+		manual    string
+		failList  bool
+		wantError string
+	}{
+		{name: "the new instance is found"},
+		{name: "a rental made before is not adopted", manual: "before"},
+		{name: "a rental made at the same time is not adopted", manual: "during", wantError: "2 new instance(s) match it"},
+		{name: "the lookup fails", failList: true, wantError: "its ID could not be looked up"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []string
+			client := newInterceptClient(t, func(w http.ResponseWriter, r *http.Request, backend http.Handler) {
+				switch r.Method + " " + r.URL.Path {
+				case routeRentSpot:
+					if tt.manual == "during" {
+						rentOnMock(t, backend, "mock-h100-1")
+					}
+					// The rental is made, but the response has no ID
+					recorder := httptest.NewRecorder()
+					backend.ServeHTTP(recorder, r)
+					var response hyperbolic.SpotRentResponse
+					json.Unmarshal(recorder.Body.Bytes(), &response)
+					created = append(created, response.InstanceID)
+					response.InstanceID = ""
+					w.Header().Set("Content-Type", "application/json")
+					json.NewEncoder(w).Encode(response)
+				case routeListInstances:
+					if tt.failList && len(created) > 0 {
+						http.Error(w, `{"message": "unavailable"}`, http.StatusBadGateway)
+						return
+					}
+					backend.ServeHTTP(w, r)
+				default:
+					backend.ServeHTTP(w, r)
+				}
+			})
+
+			run := &rentalPlanRun{client: client, instances: map[string]hyperbolic.Instance{}}
+			if tt.manual == "before" {
+				// Made after the plan, so unknown to it, but before the create
+				if _, err := client.RentSpotInstance(context.Background(), hyperbolic.RentRequest{ClusterName: "mock-cluster-east", NodeName: "mock-h100-1", GpuCount: 1}); err != nil {
+					t.Fatalf("renting by hand: %v", err)
+				}
+				created = nil
+			}
+
+			id, err := run.create(context.Background(), rental, action)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("got ID %q and error %v, want an error containing %q", id, err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(created) != 1 || id != created[0] {
+				t.Errorf("got ID %q, want %v", id, created)
+			}
+		})
+	}
+}
+
+func TestRentalPlanRunCreateFails(t *testing.T) {
+	rental := rentalSpec{Name: "trainer", Type: string(hyperbolic.InstanceTypeSpot), GPUCount: 1}
+	action := rentalPlanActionOutput{Action: planActionCreate, Rental: "trainer", Type: rental.Type, GPUCount: 1, Cluster: "mock-cluster-east", Node: "mock-h100-1"}
+
+	tests := []struct {
+		name   string
+		status int
+		// made is set when the mock makes the rental before failing
+		made      bool
+		manual    bool
+		wantError string
+	}{
+		{name: "a rental made despite a server error is adopted", status: http.StatusInternalServerError, made: true},
+		{name: "a server error without a rental", status: http.StatusInternalServerError, wantError: "check 'hyperbolic instances'"},
+		{name: "a server error with two matches", status: http.StatusInternalServerError, made: true, manual: true, wantError: "check 'hyperbolic instances'"},
+		{name: "a client error is not looked up", status: http.StatusBadRequest, made: true, wantError: "rental request failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr := captureStderr(t)
+			var created []string
+			client := newInterceptClient(t, func(w http.ResponseWriter, r *http.Request, backend http.Handler) {
+				if r.Method+" "+r.URL.Path != routeRentSpot {
+					backend.ServeHTTP(w, r)
+					return
+				}
+				if tt.manual {
+					rentOnMock(t, backend, "mock-h100-1")
+				}
+				if tt.made {
+					recorder := httptest.NewRecorder()
+					backend.ServeHTTP(recorder, r)
+					var response hyperbolic.SpotRentResponse
+					json.Unmarshal(recorder.Body.Bytes(), &response)
+					created = append(created, response.InstanceID)
+				}
+				http.Error(w, `{"message": "failed"}`, tt.status)
+			})
+
+			run := &rentalPlanRun{client: client, instances: map[string]hyperbolic.Instance{}}
+			id, err := run.create(context.Background(), rental, action)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("got ID %q and error %v, want an error containing %q", id, err, tt.wantError)
+				}
+				if tt.status < 500 && strings.Contains(err.Error(), "may have been made") {
+					t.Errorf("error %q suggests a rental after a client error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(created) != 1 || id != created[0] {
+				t.Errorf("got ID %q, want %v", id, created)
+			}
+			if _, ok := run.instances[id]; !ok {
+				t.Errorf("instance %s is not managed by the run", id)
+			}
+			if !strings.Contains(stderr(), "the rental request failed") {
+				t.Errorf("stderr does not mention the failed request:\n%s", stderr())
+			}
+		})
+	}
+}
+
+func TestRentalSpecMaxPrice(t *testing.T) {
+	tests := []struct {
+		maxPrice float64
+		valid    bool
+	}{
+		{0, true},
+		{0.5, true},
+		{0.01, true},
+		{0.005, true},
+		{0.004, false},
+		{0.0001, false},
+		{-1, false},
+	}
+	for _, tt := range tests {
+		rental := rentalSpec{Name: "dev", Type: string(hyperbolic.InstanceTypeSpot), MaxPrice: tt.maxPrice}
+		if err := rental.validate(); (err == nil) != tt.valid {
+			t.Errorf("maxPrice %g: got error %v, want valid %v", tt.maxPrice, err, tt.valid)
+		}
+	}
+}
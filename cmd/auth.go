@@ -4,43 +4,254 @@ Copyright © 2025 Hyperbolic Labs
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"sort"
 	"strings"
 
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/credentials"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // authCmd represents the auth command
 var authCmd = &cobra.Command{
-	Use:   "auth <api-key>",
+	Use:   "auth [api-key]",
 	Short: "Authenticate with your Hyperbolic API key",
-	Long: `Add your Hyperbolic API key for CLI usage. Create one at https://app.hyperbolic.ai/settings.`,
-	Example: `hyperbolic auth your-hyperbolic-api-key`,
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		apiKey := strings.TrimSpace(args[0])
-		
+	Long: `Add your Hyperbolic API key for CLI usage. Create one at https://app.hyperbolic.ai/settings.
+
+Run without an argument to be prompted for the key without echoing it, or pass --stdin to read it from standard input. Both keep the key out of your shell history and process list.
+
+The key is checked against the API before it is saved; pass --no-verify to skip the check, e.g. when offline.
+
+The API key is resolved in this order (highest precedence first):
+  1. The --api-key flag
+  2. The HYPERBOLIC_API_KEY environment variable
+  3. The profile selected with --profile or HYPERBOLIC_PROFILE
+  4. The current profile in ~/.hyperbolic/config.json
+
+The key is stored in the OS keyring (Secret Service on Linux, Keychain on macOS, Credential Manager on Windows) when one is available. Otherwise it falls back to an encrypted file, ~/.hyperbolic/credentials.enc, protected by a passphrase from HYPERBOLIC_CREDENTIALS_PASSPHRASE or a prompt, and as a last resort to plaintext in ~/.hyperbolic/config.json. Choose a store explicitly with --store keyring|encrypted|file.`,
+	Example: `  hyperbolic auth
+  hyperbolic auth --profile research
+  hyperbolic auth --store encrypted
+  echo "$HYPERBOLIC_KEY" | hyperbolic auth --stdin
+  hyperbolic auth your-hyperbolic-api-key
+  hyperbolic auth status
+  hyperbolic auth verify
+  hyperbolic auth logout`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromStdin, _ := cmd.Flags().GetBool("stdin")
+		storeName, _ := cmd.Flags().GetString("store")
+
+		switch storeName {
+		case "auto", credentials.StoreKeyring, credentials.StoreEncrypted, credentials.StoreFile:
+		default:
+			return usageErrorf("invalid --store '%s'. Must be 'keyring', 'encrypted' or 'file'", storeName)
+		}
+
+		apiKey, err := readAPIKey(args, fromStdin)
+		if err != nil {
+			return err
+		}
+
 		if apiKey == "" {
-			fmt.Println("Error: API key cannot be empty")
-			return
+			return usageErrorf("API key cannot be empty")
+		}
+
+		noVerify, _ := cmd.Flags().GetBool("no-verify")
+		if !noVerify {
+			if _, err := verifyAPIKey(cmd, apiKey); err != nil {
+				var apiErr *hyperbolic.APIError
+				if errors.As(err, &apiErr) && apiErr.IsUnauthorized() {
+					return err
+				}
+				return fmt.Errorf("%w\nUse --no-verify to save the key without checking it", err)
+			}
 		}
-		
-		// Create config with the API key
-		config := &Config{
-			APIKey: apiKey,
+
+		config, err := loadOrCreateConfig()
+		if err != nil {
+			return fmt.Errorf("error loading configuration: %w", err)
 		}
-		
+
+		// Store the key for the selected profile, keeping its other settings
+		name := config.ActiveProfileName()
+		usedStore, err := saveProfileAPIKey(config, name, apiKey, storeName)
+		if err != nil {
+			return fmt.Errorf("error saving API key: %w", err)
+		}
+
+		// The first profile saved becomes the current one
+		if config.CurrentProfile == "" && len(config.Profiles) == 1 {
+			config.CurrentProfile = name
+		}
+
 		// Save the config
 		if err := SaveConfig(config); err != nil {
-			fmt.Printf("Error saving configuration: %v\n", err)
-			return
+			return fmt.Errorf("error saving configuration: %w", err)
+		}
+
+		if usedStore == credentials.StoreFile && storeName == "auto" {
+			fmt.Fprintln(os.Stderr, "Warning: API key stored in plaintext in ~/.hyperbolic/config.json")
 		}
-		
-		fmt.Println("✓ API key saved successfully!")
+		fmt.Printf("✓ API key saved successfully to profile '%s' (%s store)!\n", name, usedStore)
 		fmt.Println("You can now use other commands like 'hyperbolic rent' without setting environment variables.")
+		return nil
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which API key is active and where it comes from.",
+	Long:  `Show the active profile, where its API key was found, and the key with all but its first and last four characters hidden. The key is not checked against the API; use 'hyperbolic auth verify' for that.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiKey, source, err := resolveAPIKey()
+		if err != nil {
+			return &AuthError{Err: err}
+		}
+
+		config, _ := LoadConfig()
+		fmt.Printf("Profile: %s\n", config.ActiveProfileName())
+		fmt.Printf("Source:  %s\n", source)
+		fmt.Printf("API key: %s\n", maskAPIKey(apiKey))
+		fmt.Printf("API URL: %s\n", apiBaseURL())
+		return nil
 	},
 }
 
+var authVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that the active API key is accepted by the API.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiKey, source, err := resolveAPIKey()
+		if err != nil {
+			return &AuthError{Err: err}
+		}
+
+		user, err := verifyAPIKey(cmd, apiKey)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ API key from %s is valid for %s\n", source, user.Email)
+		return nil
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored API key.",
+	Long:  `Remove the API key stored for the active profile, or for every profile with --all. Other profile settings such as the API URL are kept.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+
+		config, err := LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		names := []string{config.ActiveProfileName()}
+		if all {
+			names = names[:0]
+			for name := range config.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		} else if _, exists := config.Profiles[names[0]]; !exists {
+			return &NotFoundError{Kind: "profile", ID: names[0]}
+		}
+
+		for _, name := range names {
+			if err := deleteProfileAPIKey(config, name); err != nil {
+				return fmt.Errorf("error removing API key for profile '%s': %w", name, err)
+			}
+			profile := config.Profiles[name]
+			profile.APIKey = ""
+			profile.CredentialStore = ""
+			// Drop profiles that have nothing left in them
+			if profile.APIURL == "" {
+				delete(config.Profiles, name)
+				if config.CurrentProfile == name {
+					config.CurrentProfile = ""
+				}
+			}
+		}
+
+		if err := SaveConfig(config); err != nil {
+			return fmt.Errorf("error saving configuration: %w", err)
+		}
+
+		for _, name := range names {
+			fmt.Printf("✓ Removed API key for profile '%s'\n", name)
+		}
+		if os.Getenv("HYPERBOLIC_API_KEY") != "" {
+			fmt.Fprintln(os.Stderr, "Note: HYPERBOLIC_API_KEY is still set and will be used by other commands")
+		}
+		return nil
+	},
+}
+
+// verifyAPIKey checks apiKey against /users/me and returns the account it
+// belongs to
+func verifyAPIKey(cmd *cobra.Command, apiKey string) (hyperbolic.UserResponse, error) {
+	client := hyperbolic.NewClient(apiKey, clientOptions()...)
+	user, err := client.GetUser(cmd.Context())
+	if err == nil {
+		return user, nil
+	}
+
+	var apiErr *hyperbolic.APIError
+	if errors.As(err, &apiErr) && apiErr.IsUnauthorized() {
+		return user, fmt.Errorf("API key was rejected: %w", err)
+	}
+	return user, fmt.Errorf("could not verify API key: %w", err)
+}
+
+// readAPIKey returns the API key from the command line, standard input, or
+// an interactive prompt that does not echo the key
+func readAPIKey(args []string, fromStdin bool) (string, error) {
+	if len(args) > 0 {
+		if fromStdin {
+			return "", usageErrorf("cannot use --stdin together with an API key argument")
+		}
+		return strings.TrimSpace(args[0]), nil
+	}
+
+	if fromStdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("error reading API key from stdin: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", usageErrorf("no API key given; pass it as an argument, pipe it with --stdin, or run interactively")
+	}
+
+	fmt.Fprint(os.Stderr, "Enter your Hyperbolic API key: ")
+	data, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("error reading API key: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
 func init() {
 	rootCmd.AddCommand(authCmd)
-} 
\ No newline at end of file
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authVerifyCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.Flags().Bool("stdin", false, "Read the API key from standard input")
+	authCmd.Flags().Bool("no-verify", false, "Save the API key without checking it against the API")
+	authCmd.Flags().String("store", "auto", "Where to store the API key: keyring, encrypted or file (default tries each in turn)")
+	authLogoutCmd.Flags().Bool("all", false, "Remove the API keys of every profile")
+}
@@ -0,0 +1,234 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+// defaultCapacityPollInterval is how often --until-available checks the
+// marketplace by default
+const defaultCapacityPollInterval = 10 * time.Second
+
+// untilAvailableOptions are the settings of 'rent spot --until-available'
+type untilAvailableOptions struct {
+	enabled      bool
+	pollInterval time.Duration
+	// deadline is zero to keep trying until interrupted
+	deadline      time.Time
+	notifyCommand string
+}
+
+// addUntilAvailableFlags adds the flags that keep retrying a spot rental
+func addUntilAvailableFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("until-available", false, "Keep checking the marketplace until a node meeting the requirements has free GPUs, then rent it (implies --auto)")
+	cmd.Flags().Duration("poll-interval", defaultCapacityPollInterval, "How often --until-available checks the marketplace")
+	cmd.Flags().String("deadline", "", "When --until-available gives up, as a duration (e.g. 2h) or an RFC 3339 time (default: never)")
+	cmd.Flags().String("notify-command", "", "Shell command to run once --until-available has rented an instance")
+}
+
+// untilAvailableFromFlags reads and checks the --until-available flags
+func untilAvailableFromFlags(cmd *cobra.Command) (untilAvailableOptions, error) {
+	var options untilAvailableOptions
+	options.enabled, _ = cmd.Flags().GetBool("until-available")
+	options.pollInterval, _ = cmd.Flags().GetDuration("poll-interval")
+	deadline, _ := cmd.Flags().GetString("deadline")
+	options.notifyCommand, _ = cmd.Flags().GetString("notify-command")
+
+	if !options.enabled {
+		for _, name := range []string{"poll-interval", "deadline", "notify-command"} {
+			if cmd.Flags().Changed(name) {
+				return options, usageErrorf("--%s can only be used with --until-available", name)
+			}
+		}
+		return options, nil
+	}
+	if options.pollInterval < time.Second {
+		return options, usageErrorf("--poll-interval must be at least 1s")
+	}
+	if deadline != "" {
+		var err error
+		if options.deadline, err = parseDeadline(deadline, time.Now()); err != nil {
+			return options, err
+		}
+	}
+	return options, nil
+}
+
+// parseDeadline parses --deadline, which is a duration from now or a time
+func parseDeadline(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return time.Time{}, usageErrorf("--deadline must be a positive duration")
+		}
+		return now.Add(d), nil
+	}
+	deadline, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, usageErrorf("invalid --deadline '%s': use a duration such as 2h30m or a time such as 2025-07-01T09:00:00Z", value)
+	}
+	if !deadline.After(now) {
+		return time.Time{}, usageErrorf("--deadline %s has already passed", value)
+	}
+	return deadline, nil
+}
+
+// rentWhenAvailable checks the marketplace every poll interval until a node
+// matching filter has enough free GPUs, then rents on it at once, as
+// rentCheapestSpotNode does. At most one rental is made: if a create call
+// fails without saying whether the rental was made, no other is attempted
+// until the caller's instances show it was not.
+func rentWhenAvailable(ctx context.Context, client *hyperbolic.Client, filter hyperbolic.MarketplaceFilter, request hyperbolic.RentRequest, options untilAvailableOptions) (hyperbolic.SpotRentResponse, hyperbolic.RentRequest, error) {
+	// Instances that existed before, so a new rental can be recognized
+	instances, err := client.ListInstances(ctx)
+	if err != nil {
+		return hyperbolic.SpotRentResponse{}, request, fmt.Errorf("error fetching instances: %w", err)
+	}
+	known := map[string]bool{}
+	for _, instance := range instances {
+		known[instance.ID] = true
+	}
+
+	// The deadline only limits waiting, so a create call in progress is
+	// never cut short and left with an unknown outcome
+	pollCtx := ctx
+	if !options.deadline.IsZero() {
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithDeadline(ctx, options.deadline)
+		defer cancel()
+	}
+
+	fmt.Printf("Waiting for a spot node with %d free GPU(s) that meets the requirements, checking every %s", request.GpuCount, options.pollInterval)
+	if !options.deadline.IsZero() {
+		fmt.Printf(" until %s", options.deadline.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Println("...")
+
+	start := time.Now()
+	lastStatus := ""
+	status := func(message string) {
+		if message != lastStatus {
+			fmt.Printf("  [%s] %s\n", formatElapsed(time.Since(start)), message)
+			lastStatus = message
+		}
+	}
+
+	// uncertain is set while the outcome of a create call is unknown
+	var uncertain *hyperbolic.RentRequest
+	for {
+		if uncertain != nil {
+			id, err := findNewSpotRental(ctx, client, known, *uncertain)
+			switch {
+			case err != nil:
+				status(fmt.Sprintf("could not check whether the rental was made: %v", err))
+			case id != "":
+				return hyperbolic.SpotRentResponse{InstanceID: id}, *uncertain, nil
+			default:
+				status("the rental was not made; checking the marketplace again")
+				uncertain = nil
+			}
+		}
+
+		if uncertain == nil {
+			marketplaceData, err := client.ListMarketplace(pollCtx, nil)
+			var apiErr *hyperbolic.APIError
+			switch {
+			case err != nil && errors.As(err, &apiErr) && apiErr.IsUnauthorized():
+				return hyperbolic.SpotRentResponse{}, request, fmt.Errorf("error calling Hyperbolic API: %w", err)
+			case err != nil && pollCtx.Err() == nil:
+				status(fmt.Sprintf("could not check the marketplace: %v", err))
+			case err == nil:
+				candidates := filterMarketplaceInstances(marketplaceData.Instances, filter, false)
+				if len(candidates) == 0 {
+					status(fmt.Sprintf("no matching node has %d free GPU(s) yet", request.GpuCount))
+					break
+				}
+
+				lastStatus = ""
+				response, rented, err := rentOnSpotNodes(ctx, client, candidates, request)
+				if err == nil {
+					return response, rented, nil
+				}
+				var networkErr *hyperbolic.NetworkError
+				switch {
+				case errors.As(err, &apiErr) && apiErr.IsCapacityUnavailable():
+					// Every candidate was taken first
+				case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests:
+					status("the API is rate limiting rentals; trying again")
+				case errors.As(err, &networkErr) || errors.As(err, &apiErr) && apiErr.IsServerError():
+					status(fmt.Sprintf("the rental on %s may have been made (%v); checking your instances before trying again", rented.NodeName, err))
+					uncertain = &rented
+				default:
+					return response, rented, err
+				}
+			}
+		}
+
+		select {
+		case <-pollCtx.Done():
+			if ctx.Err() != nil {
+				return hyperbolic.SpotRentResponse{}, request, ctx.Err()
+			}
+			if uncertain != nil {
+				return hyperbolic.SpotRentResponse{}, request, &TimeoutError{Err: fmt.Errorf("gave up at the deadline without knowing whether the rental on %s was made; run 'hyperbolic instances' to check", uncertain.NodeName)}
+			}
+			return hyperbolic.SpotRentResponse{}, request, &TimeoutError{Err: fmt.Errorf("no matching node had %d free GPU(s) before the deadline (%s)", request.GpuCount, formatElapsed(time.Since(start)))}
+		case <-time.After(options.pollInterval):
+		}
+	}
+}
+
+// findNewSpotRental returns the ID of a spot rental on the request's node
+// that is not among the known instances, or "" if there is none. Rentals
+// carry no tag to tell whose they are, so it matches on the node and GPU
+// count only: a rental of the same size on the same node made by other means
+// while the outcome was unknown, such as by hand, would be taken for this one.
+func findNewSpotRental(ctx context.Context, client *hyperbolic.Client, known map[string]bool, request hyperbolic.RentRequest) (string, error) {
+	instances, err := client.ListInstances(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, instance := range instances {
+		if known[instance.ID] || instance.Spot == nil || instance.HasEnded() {
+			continue
+		}
+		if instance.Spot.Instance.ID == request.NodeName && instance.GPUCount == request.GpuCount {
+			return instance.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// runNotifyCommand runs the --notify-command hook in a shell, with the
+// rental's details in its environment. The rental has been made whatever
+// the hook does, so a failure is only reported.
+func runNotifyCommand(command string, instanceID string, request hyperbolic.RentRequest) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	hook := exec.Command(shell, flag, command)
+	hook.Env = append(os.Environ(),
+		"HYPERBOLIC_INSTANCE_ID="+instanceID,
+		"HYPERBOLIC_CLUSTER_NAME="+request.ClusterName,
+		"HYPERBOLIC_NODE_NAME="+request.NodeName,
+		"HYPERBOLIC_GPU_COUNT="+strconv.Itoa(request.GpuCount),
+	)
+	hook.Stdout = os.Stdout
+	hook.Stderr = os.Stderr
+	if err := hook.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: --notify-command failed: %v\n", err)
+	}
+}
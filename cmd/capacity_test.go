@@ -0,0 +1,133 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+func TestRentWhenAvailableServerError(t *testing.T) {
+	tests := []struct {
+		name string
+		// made is whether the failed create call rented the instance
+		made    bool
+		creates int
+	}{
+		{"the failed rental was made", true, 1},
+		{"the failed rental was not made", false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []string
+			client := newInterceptClient(t, func(w http.ResponseWriter, r *http.Request, backend http.Handler) {
+				if r.Method+" "+r.URL.Path != routeRentSpot {
+					backend.ServeHTTP(w, r)
+					return
+				}
+				created = append(created, "")
+				if len(created) > 1 {
+					backend.ServeHTTP(w, r)
+					return
+				}
+				// The first create fails with a server error, whether or not
+				// the rental was made
+				if tt.made {
+					created[0] = rentOnMock(t, backend, "mock-h100-1")
+				}
+				http.Error(w, `{"message": "bad gateway"}`, http.StatusBadGateway)
+			})
+
+			filter := hyperbolic.MarketplaceFilter{GPUModel: "h100", MinGPUs: 1}
+			request := hyperbolic.RentRequest{GpuCount: 1}
+			options := untilAvailableOptions{enabled: true, pollInterval: time.Millisecond, deadline: time.Now().Add(time.Minute)}
+			response, rented, err := rentWhenAvailable(context.Background(), client, filter, request, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(created) != tt.creates {
+				t.Errorf("made %d create call(s), want %d", len(created), tt.creates)
+			}
+			if rented.NodeName != "mock-h100-1" {
+				t.Errorf("rented on %q, want mock-h100-1", rented.NodeName)
+			}
+			if tt.made && response.InstanceID != created[0] {
+				t.Errorf("got instance %q, want the one made by the failed call, %q", response.InstanceID, created[0])
+			}
+			if !tt.made && response.InstanceID == "" {
+				t.Error("got no instance ID from the second create call")
+			}
+		})
+	}
+}
+
+func TestRentWhenAvailableRateLimited(t *testing.T) {
+	creates := 0
+	client := newInterceptClient(t, func(w http.ResponseWriter, r *http.Request, backend http.Handler) {
+		if r.Method+" "+r.URL.Path != routeRentSpot {
+			backend.ServeHTTP(w, r)
+			return
+		}
+		creates++
+		if creates == 1 {
+			http.Error(w, `{"message": "too many requests"}`, http.StatusTooManyRequests)
+			return
+		}
+		backend.ServeHTTP(w, r)
+	})
+
+	filter := hyperbolic.MarketplaceFilter{GPUModel: "h100", MinGPUs: 1}
+	request := hyperbolic.RentRequest{GpuCount: 1}
+	options := untilAvailableOptions{enabled: true, pollInterval: time.Millisecond, deadline: time.Now().Add(time.Minute)}
+	response, _, err := rentWhenAvailable(context.Background(), client, filter, request, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creates != 2 {
+		t.Errorf("made %d create call(s), want 2", creates)
+	}
+	if response.InstanceID == "" {
+		t.Error("got no instance ID from the second create call")
+	}
+}
+
+func TestFindNewSpotRental(t *testing.T) {
+	var existing string
+	client := newInterceptClient(t, func(w http.ResponseWriter, r *http.Request, backend http.Handler) {
+		if existing == "" {
+			existing = rentOnMock(t, backend, "mock-h100-1")
+		}
+		backend.ServeHTTP(w, r)
+	})
+	ctx := context.Background()
+	request := hyperbolic.RentRequest{ClusterName: "mock-cluster-east", NodeName: "mock-h100-1", GpuCount: 1}
+
+	// Any rental on the node that is not known counts, however it was made
+	if id, err := findNewSpotRental(ctx, client, map[string]bool{}, request); err != nil || id != existing {
+		t.Fatalf("got %q, %v; want the existing rental %q while it is not known", id, err, existing)
+	}
+	known := map[string]bool{existing: true}
+	if id, err := findNewSpotRental(ctx, client, known, request); err != nil || id != "" {
+		t.Fatalf("got %q, %v; want no rental", id, err)
+	}
+
+	response, err := client.RentSpotInstance(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := findNewSpotRental(ctx, client, known, request); err != nil || id != response.InstanceID {
+		t.Errorf("got %q, %v; want %q", id, err, response.InstanceID)
+	}
+
+	// Only the node and GPU count are compared
+	other := request
+	other.GpuCount = 2
+	if id, err := findNewSpotRental(ctx, client, known, other); err != nil || id != "" {
+		t.Errorf("got %q, %v for another GPU count; want no rental", id, err)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+const authHint = "Please run 'hyperbolic auth' to save your API key, or set HYPERBOLIC_API_KEY\n(Get your API key from https://app.hyperbolic.ai/settings)"
+
+// Set by persistent flags on the root command
+var (
+	apiURL         string
+	requestTimeout time.Duration
+	maxRetries     int
+)
+
+// apiBaseURL resolves the API root from the --api-url flag, the
+// HYPERBOLIC_API_URL environment variable, the active profile, and finally
+// the production default, in that order.
+func apiBaseURL() string {
+	if apiURL != "" {
+		return apiURL
+	}
+	if envURL := os.Getenv("HYPERBOLIC_API_URL"); envURL != "" {
+		return envURL
+	}
+	if config, err := LoadConfig(); err == nil {
+		if profile := config.ActiveProfile(); profile != nil && profile.APIURL != "" {
+			return profile.APIURL
+		}
+	}
+	return hyperbolic.DefaultBaseURL
+}
+
+// clientOptions returns the client options selected by global flags
+func clientOptions() []hyperbolic.Option {
+	retry := hyperbolic.DefaultRetryPolicy()
+	retry.MaxRetries = maxRetries
+	return []hyperbolic.Option{
+		hyperbolic.WithBaseURL(apiBaseURL()),
+		hyperbolic.WithTimeout(requestTimeout),
+		hyperbolic.WithRetryPolicy(retry),
+	}
+}
+
+// newClient returns an API client authenticated with the stored API key
+func newClient() (*hyperbolic.Client, error) {
+	apiKey, err := GetAPIKey()
+	if err != nil {
+		return nil, &AuthError{Err: err}
+	}
+	return hyperbolic.NewClient(apiKey, clientOptions()...), nil
+}
+
+// newPublicClient returns an API client for endpoints that do not require
+// authentication. The stored API key is still sent when one is available.
+func newPublicClient() *hyperbolic.Client {
+	apiKey, _ := GetAPIKey()
+	return hyperbolic.NewClient(apiKey, clientOptions()...)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+// defaultMasterPort is the port torchrun uses for rendezvous by default
+const defaultMasterPort = 29500
+
+// Formats of 'hyperbolic cluster env'
+const (
+	clusterFormatEnv           = "env"
+	clusterFormatHostfile      = "hostfile"
+	clusterFormatJSON          = "json"
+	clusterFormatSlurmNodelist = "slurm-nodelist"
+)
+
+// clusterCmd represents the cluster command
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Set up distributed training across the nodes of an instance.",
+	Long:  `Commands for running distributed jobs across the nodes of a multi-node bare-metal rental.`,
+}
+
+// clusterEnvCmd represents the cluster env command
+var clusterEnvCmd = &cobra.Command{
+	Use:   "env <instance-id>",
+	Short: "Print launcher settings for distributed training on an instance.",
+	Long: `Print the settings distributed training launchers need for the nodes of an instance, using the nodes' private IP addresses:
+
+  env             shell exports for torchrun (MASTER_ADDR, NNODES, ...) and NCCL, with a torchrun command (default)
+  hostfile        an MPI/DeepSpeed hostfile with one line per node
+  slurm-nodelist  the node addresses separated by commas
+  json            all of the above as a ClusterEnv document
+
+NCCL settings depend on the instance's network: InfiniBand is used when the instance has it, and otherwise NCCL is limited to sockets.
+
+EXAMPLES:
+  eval "$(hyperbolic cluster env 1234)"
+  hyperbolic cluster env 1234 --format hostfile > hostfile
+  hyperbolic cluster env 1234 --format json | jq .masterAddr`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clusterFormat, _ := cmd.Flags().GetString("format")
+		masterPort, _ := cmd.Flags().GetInt("master-port")
+		if masterPort < 1 || masterPort > 65535 {
+			return usageErrorf("--master-port must be between 1 and 65535")
+		}
+
+		// -o json, yaml, go-template and jsonpath print the ClusterEnv
+		// document, as --format json does
+		format, err := selectedOutput(cmd)
+		if err != nil {
+			return err
+		}
+		if !format.IsTable() && cmd.Flags().Changed("format") && clusterFormat != clusterFormatJSON {
+			return usageErrorf("--format %s cannot be combined with -o %s", clusterFormat, format.Name)
+		}
+		if format.IsTable() && clusterFormat == clusterFormatJSON {
+			if format, err = printer.ParseFormat(printer.FormatJSON); err != nil {
+				return err
+			}
+		}
+
+		switch clusterFormat {
+		case clusterFormatEnv, clusterFormatHostfile, clusterFormatJSON, clusterFormatSlurmNodelist:
+		default:
+			return usageErrorf("invalid --format '%s'. Must be one of: %s", clusterFormat, strings.Join([]string{clusterFormatEnv, clusterFormatHostfile, clusterFormatJSON, clusterFormatSlurmNodelist}, ", "))
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		instance, err := findInstance(cmd.Context(), client, args[0])
+		if err != nil {
+			return err
+		}
+
+		env, err := newClusterEnvOutput(instance, masterPort)
+		if err != nil {
+			return err
+		}
+
+		if !format.IsTable() {
+			return printStructured(format, env, nil)
+		}
+		switch clusterFormat {
+		case clusterFormatHostfile:
+			printHostfile(os.Stdout, env)
+		case clusterFormatSlurmNodelist:
+			printSlurmNodelist(os.Stdout, env)
+		default:
+			printClusterEnv(os.Stdout, instance, env)
+		}
+		return nil
+	},
+}
+
+// newClusterEnvOutput returns the launcher settings for the nodes of
+// instance. Nodes are addressed by private IP, or by public IP or SSH host if
+// the API did not report one. It fails if the number of GPUs per node is not
+// known, rather than telling launchers to start no processes.
+func newClusterEnvOutput(instance hyperbolic.Instance, masterPort int) (clusterEnvOutput, error) {
+	if len(instance.Nodes) == 0 {
+		return clusterEnvOutput{}, fmt.Errorf("no network details available for instance %s yet (status: %s)", instance.ID, instance.Status)
+	}
+
+	gpusPerNode := instance.GPUsPerNode
+	if gpusPerNode == 0 && instance.GPUCount%len(instance.Nodes) == 0 {
+		gpusPerNode = instance.GPUCount / len(instance.Nodes)
+	}
+	if gpusPerNode < 1 {
+		return clusterEnvOutput{}, fmt.Errorf("the number of GPUs per node of instance %s is not known yet (status: %s); try again once it is running", instance.ID, instance.Status)
+	}
+
+	networkType := strings.ToLower(instance.NetworkType)
+	if networkType == "" {
+		networkType = "ethernet"
+	}
+	env := clusterEnvOutput{
+		typeMeta:     newTypeMeta(kindClusterEnv),
+		InstanceID:   instance.ID,
+		NetworkType:  networkType,
+		MasterPort:   masterPort,
+		NNodes:       len(instance.Nodes),
+		NProcPerNode: gpusPerNode,
+		WorldSize:    gpusPerNode * len(instance.Nodes),
+		Nodes:        []clusterNodeOutput{},
+		Env:          ncclEnv(networkType),
+	}
+	for i, node := range instance.Nodes {
+		address := node.PrivateIP
+		if address == "" {
+			address = node.PublicIP
+		}
+		if address == "" && node.SSH != nil {
+			address = node.SSH.Host
+		}
+		if address == "" {
+			return clusterEnvOutput{}, fmt.Errorf("no IP address available for node %d of instance %s", i+1, instance.ID)
+		}
+		env.Nodes = append(env.Nodes, clusterNodeOutput{
+			Rank:     i,
+			Address:  address,
+			PublicIP: node.PublicIP,
+			Slots:    gpusPerNode,
+		})
+	}
+	env.MasterAddr = env.Nodes[0].Address
+	return env, nil
+}
+
+// ncclEnv returns the NCCL variables for a network type. NCCL bootstraps
+// over sockets, which should avoid the loopback and Docker interfaces; with
+// InfiniBand the collectives run over the Mellanox adapters.
+func ncclEnv(networkType string) map[string]string {
+	env := map[string]string{"NCCL_SOCKET_IFNAME": "^lo,docker"}
+	if networkType == "infiniband" {
+		env["NCCL_IB_DISABLE"] = "0"
+		env["NCCL_IB_HCA"] = "mlx5"
+	} else {
+		env["NCCL_IB_DISABLE"] = "1"
+	}
+	return env
+}
+
+// printHostfile writes env as an MPI/DeepSpeed hostfile
+func printHostfile(w io.Writer, env clusterEnvOutput) {
+	for _, node := range env.Nodes {
+		fmt.Fprintf(w, "%s slots=%d\n", node.Address, node.Slots)
+	}
+}
+
+// printSlurmNodelist writes the node addresses of env separated by commas
+func printSlurmNodelist(w io.Writer, env clusterEnvOutput) {
+	var addresses []string
+	for _, node := range env.Nodes {
+		addresses = append(addresses, node.Address)
+	}
+	fmt.Fprintln(w, strings.Join(addresses, ","))
+}
+
+// printClusterEnv writes env as shell exports followed by the torchrun
+// command to start a job on every node
+func printClusterEnv(w io.Writer, instance hyperbolic.Instance, env clusterEnvOutput) {
+	fmt.Fprintf(w, "# %s instance %s: %d node(s) × %d GPU(s), %s\n", instanceTypeLabel(instance.Type), instance.ID, env.NNodes, env.NProcPerNode, env.NetworkType)
+	fmt.Fprintf(w, "export MASTER_ADDR=%s\n", env.MasterAddr)
+	fmt.Fprintf(w, "export MASTER_PORT=%d\n", env.MasterPort)
+	fmt.Fprintf(w, "export NNODES=%d\n", env.NNodes)
+	fmt.Fprintf(w, "export NPROC_PER_NODE=%d\n", env.NProcPerNode)
+	fmt.Fprintf(w, "export WORLD_SIZE=%d\n", env.WorldSize)
+
+	names := make([]string, 0, len(env.Env))
+	for name := range env.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "export %s=%s\n", name, shellQuote(env.Env[name]))
+	}
+
+	// exec runs the command in a remote shell, where the exports above are
+	// not set
+	var inline []string
+	for _, name := range names {
+		inline = append(inline, name+"="+shellQuote(env.Env[name]))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Start a job on every node; the nodes find each other through MASTER_ADDR:")
+	fmt.Fprintf(w, "#   hyperbolic exec %s -- \"%s torchrun --nnodes=%d --nproc_per_node=%d \\\n", instance.ID, strings.Join(inline, " "), env.NNodes, env.NProcPerNode)
+	fmt.Fprintf(w, "#     --rdzv_backend=c10d --rdzv_endpoint=%s:%d --rdzv_id=%s train.py\"\n", env.MasterAddr, env.MasterPort, sshHostAlias(instance, 0))
+}
+
+func init() {
+	rootCmd.AddCommand(clusterCmd)
+	clusterCmd.AddCommand(clusterEnvCmd)
+	clusterEnvCmd.Flags().String("format", clusterFormatEnv, "Output format: env, hostfile, json or slurm-nodelist")
+	clusterEnvCmd.Flags().Int("master-port", defaultMasterPort, "Port for the rendezvous on the first node")
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+func TestClusterEnvFormats(t *testing.T) {
+	infiniband := hyperbolic.Instance{
+		ID:          "1002",
+		Type:        hyperbolic.InstanceTypeBareMetal,
+		Status:      "running",
+		GPUCount:    16,
+		GPUsPerNode: 8,
+		NodeCount:   2,
+		NetworkType: "InfiniBand",
+		Nodes: []hyperbolic.InstanceNode{
+			{PublicIP: "203.0.113.3", PrivateIP: "10.10.0.1"},
+			{PublicIP: "203.0.113.4", PrivateIP: "10.10.0.2"},
+		},
+	}
+	// Without private IPs the nodes are addressed by public IP, and the GPUs
+	// per node come from the total
+	ethernet := hyperbolic.Instance{
+		ID:          "2001",
+		Type:        hyperbolic.InstanceTypeBareMetal,
+		Status:      "running",
+		GPUCount:    24,
+		NetworkType: "ethernet",
+		Nodes: []hyperbolic.InstanceNode{
+			{PublicIP: "198.51.100.1"},
+			{PublicIP: "198.51.100.2"},
+			{PublicIP: "198.51.100.3"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		instance hyperbolic.Instance
+		hostfile string
+		nodelist string
+		env      string
+	}{
+		{
+			name:     "infiniband",
+			instance: infiniband,
+			hostfile: "10.10.0.1 slots=8\n10.10.0.2 slots=8\n",
+			nodelist: "10.10.0.1,10.10.0.2\n",
+			env: `# Bare Metal instance 1002: 2 node(s) × 8 GPU(s), infiniband
+export MASTER_ADDR=10.10.0.1
+export MASTER_PORT=29500
+export NNODES=2
+export NPROC_PER_NODE=8
+export WORLD_SIZE=16
+export NCCL_IB_DISABLE=0
+export NCCL_IB_HCA=mlx5
+export NCCL_SOCKET_IFNAME='^lo,docker'
+
+# Start a job on every node; the nodes find each other through MASTER_ADDR:
+#   hyperbolic exec 1002 -- "NCCL_IB_DISABLE=0 NCCL_IB_HCA=mlx5 NCCL_SOCKET_IFNAME='^lo,docker' torchrun --nnodes=2 --nproc_per_node=8 \
+#     --rdzv_backend=c10d --rdzv_endpoint=10.10.0.1:29500 --rdzv_id=hyperbolic-1002 train.py"
+`,
+		},
+		{
+			name:     "ethernet",
+			instance: ethernet,
+			hostfile: "198.51.100.1 slots=8\n198.51.100.2 slots=8\n198.51.100.3 slots=8\n",
+			nodelist: "198.51.100.1,198.51.100.2,198.51.100.3\n",
+			env: `# Bare Metal instance 2001: 3 node(s) × 8 GPU(s), ethernet
+export MASTER_ADDR=198.51.100.1
+export MASTER_PORT=29500
+export NNODES=3
+export NPROC_PER_NODE=8
+export WORLD_SIZE=24
+export NCCL_IB_DISABLE=1
+export NCCL_SOCKET_IFNAME='^lo,docker'
+
+# Start a job on every node; the nodes find each other through MASTER_ADDR:
+#   hyperbolic exec 2001 -- "NCCL_IB_DISABLE=1 NCCL_SOCKET_IFNAME='^lo,docker' torchrun --nnodes=3 --nproc_per_node=8 \
+#     --rdzv_backend=c10d --rdzv_endpoint=198.51.100.1:29500 --rdzv_id=hyperbolic-2001 train.py"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := newClusterEnvOutput(tt.instance, defaultMasterPort)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var hostfile, nodelist, exports strings.Builder
+			printHostfile(&hostfile, env)
+			printSlurmNodelist(&nodelist, env)
+			printClusterEnv(&exports, tt.instance, env)
+			for _, output := range []struct{ format, got, want string }{
+				{clusterFormatHostfile, hostfile.String(), tt.hostfile},
+				{clusterFormatSlurmNodelist, nodelist.String(), tt.nodelist},
+				{clusterFormatEnv, exports.String(), tt.env},
+			} {
+				if output.got != output.want {
+					t.Errorf("--format %s:\ngot:\n%s\nwant:\n%s", output.format, output.got, output.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterEnvUnknownGPUCount(t *testing.T) {
+	for _, instance := range []hyperbolic.Instance{
+		{ID: "3001", Status: "starting", Nodes: []hyperbolic.InstanceNode{{PrivateIP: "10.0.0.1"}}},
+		// 12 GPUs do not split evenly over 8 nodes
+		{ID: "3002", Status: "running", GPUCount: 12, Nodes: make([]hyperbolic.InstanceNode, 8)},
+	} {
+		_, err := newClusterEnvOutput(instance, defaultMasterPort)
+		if err == nil || !strings.Contains(err.Error(), "GPUs per node") {
+			t.Errorf("instance %s: got %v, want an error about the GPU count", instance.ID, err)
+		}
+	}
+}
@@ -2,13 +2,80 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/credentials"
 )
 
+// DefaultProfile is the profile used when none is selected
+const DefaultProfile = "default"
+
+// Profile holds the credentials and settings for one Hyperbolic account
+type Profile struct {
+	APIKey string `json:"api_key,omitempty"`
+	APIURL string `json:"api_url,omitempty"`
+	// CredentialStore names where the API key is kept: "keyring",
+	// "encrypted", or empty for APIKey in this file
+	CredentialStore string `json:"credential_store,omitempty"`
+}
+
 type Config struct {
-	APIKey string `json:"api_key"`
+	CurrentProfile string              `json:"current_profile,omitempty"`
+	Profiles       map[string]*Profile `json:"profiles"`
+
+	// Legacy single-account fields. Files written before profiles existed
+	// are migrated into the default profile when loaded.
+	APIKey string `json:"api_key,omitempty"`
+	APIURL string `json:"api_url,omitempty"`
+}
+
+// Set by persistent flags on the root command
+var (
+	profileName string
+	apiKeyFlag  string
+)
+
+// ActiveProfileName returns the selected profile: the --profile flag, the
+// HYPERBOLIC_PROFILE environment variable, the config file's current
+// profile, and finally the default profile, in that order.
+func (c *Config) ActiveProfileName() string {
+	if profileName != "" {
+		return profileName
+	}
+	if envProfile := os.Getenv("HYPERBOLIC_PROFILE"); envProfile != "" {
+		return envProfile
+	}
+	if c != nil && c.CurrentProfile != "" {
+		return c.CurrentProfile
+	}
+	return DefaultProfile
+}
+
+// ActiveProfile returns the selected profile, or nil if it does not exist
+func (c *Config) ActiveProfile() *Profile {
+	if c == nil {
+		return nil
+	}
+	return c.Profiles[c.ActiveProfileName()]
+}
+
+// migrate moves legacy single-account fields into the default profile
+func (c *Config) migrate() {
+	if c.Profiles == nil {
+		c.Profiles = map[string]*Profile{}
+	}
+	if c.APIKey == "" && c.APIURL == "" {
+		return
+	}
+	if _, exists := c.Profiles[DefaultProfile]; !exists {
+		c.Profiles[DefaultProfile] = &Profile{APIKey: c.APIKey, APIURL: c.APIURL}
+	}
+	c.APIKey = ""
+	c.APIURL = ""
 }
 
 // getConfigDir returns the directory where config files should be stored
@@ -17,7 +84,7 @@ func getConfigDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to get user home directory: %v", err)
 	}
-	
+
 	configDir := filepath.Join(homeDir, ".hyperbolic")
 	return configDir, nil
 }
@@ -28,7 +95,7 @@ func getConfigPath() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	
+
 	return filepath.Join(configDir, "config.json"), nil
 }
 
@@ -38,28 +105,28 @@ func SaveConfig(config *Config) error {
 	if err != nil {
 		return err
 	}
-	
+
 	// Create config directory if it doesn't exist
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %v", err)
 	}
-	
+
 	configPath, err := getConfigPath()
 	if err != nil {
 		return err
 	}
-	
+
 	// Marshal config to JSON
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %v", err)
 	}
-	
+
 	// Write to file with restricted permissions
 	if err := os.WriteFile(configPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %v", err)
 	}
-	
+
 	return nil
 }
 
@@ -69,37 +136,80 @@ func LoadConfig() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	
+
 	// Check if config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("config file not found - please run 'hyperbolic auth' first")
 	}
-	
+
 	// Read config file
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %v", err)
 	}
-	
+
 	// Unmarshal JSON
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %v", err)
 	}
-	
+	config.migrate()
+
 	return &config, nil
 }
 
-// GetAPIKey returns the stored API key
+// loadOrCreateConfig loads the configuration from disk, or returns an empty
+// configuration if no config file exists yet
+func loadOrCreateConfig() (*Config, error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return &Config{Profiles: map[string]*Profile{}}, nil
+	}
+	return LoadConfig()
+}
+
+// GetAPIKey returns the API key to use. See resolveAPIKey for precedence.
 func GetAPIKey() (string, error) {
+	apiKey, _, err := resolveAPIKey()
+	return apiKey, err
+}
+
+// resolveAPIKey returns the API key and a description of where it came from.
+// Keys are taken from, in order of precedence: the --api-key flag, the
+// HYPERBOLIC_API_KEY environment variable, the profile selected with
+// --profile or HYPERBOLIC_PROFILE, and the config file's current profile.
+func resolveAPIKey() (string, string, error) {
+	if apiKeyFlag != "" {
+		return apiKeyFlag, "--api-key flag", nil
+	}
+	if envKey := strings.TrimSpace(os.Getenv("HYPERBOLIC_API_KEY")); envKey != "" {
+		return envKey, "HYPERBOLIC_API_KEY environment variable", nil
+	}
+
 	config, err := LoadConfig()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	
-	if config.APIKey == "" {
-		return "", fmt.Errorf("no API key found in config - please run 'hyperbolic auth' first")
+
+	name := config.ActiveProfileName()
+	profile := config.ActiveProfile()
+	if profile == nil {
+		return "", "", fmt.Errorf("no API key found for profile '%s' - please run 'hyperbolic auth' first", name)
 	}
-	
-	return config.APIKey, nil
-} 
\ No newline at end of file
+
+	apiKey, err := profileAPIKey(config, name)
+	if errors.Is(err, credentials.ErrNotFound) {
+		return "", "", fmt.Errorf("no API key found for profile '%s' - please run 'hyperbolic auth' first", name)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read API key for profile '%s': %w", name, err)
+	}
+
+	if profile.CredentialStore == "" {
+		return apiKey, fmt.Sprintf("profile '%s' in ~/.hyperbolic/config.json", name), nil
+	}
+	return apiKey, fmt.Sprintf("profile '%s' in the %s credential store", name, profile.CredentialStore), nil
+}
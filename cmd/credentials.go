@@ -0,0 +1,220 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/credentials"
+	"golang.org/x/term"
+)
+
+// keyringBackend is the keyring used by the keyring credential store. It is
+// a variable so tests can substitute a credentials.MemoryKeyring.
+var keyringBackend credentials.Keyring
+
+// credentialStore returns the named credential store. The "file" store keeps
+// keys in config itself, so changes to it are only persisted by SaveConfig.
+func credentialStore(name string, config *Config) (credentials.Store, error) {
+	switch name {
+	case credentials.StoreKeyring:
+		store := credentials.NewKeyringStore()
+		if keyringBackend != nil {
+			store.Keyring = keyringBackend
+		}
+		return store, nil
+	case credentials.StoreEncrypted:
+		configDir, err := getConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(configDir, "credentials.enc")
+		return &credentials.EncryptedFileStore{
+			Path: path,
+			Passphrase: func() (string, error) {
+				// A new file is encrypted with whatever is typed, so a typo
+				// would lock the key away; have it typed twice
+				_, err := os.Stat(path)
+				return credentialsPassphrase(os.IsNotExist(err))
+			},
+		}, nil
+	case credentials.StoreFile, "":
+		return &configFileStore{config: config}, nil
+	default:
+		return nil, usageErrorf("unknown credential store '%s'. Must be 'keyring', 'encrypted' or 'file'", name)
+	}
+}
+
+// profileAPIKey returns the API key for a profile from whichever store holds it
+func profileAPIKey(config *Config, name string) (string, error) {
+	profile := config.Profiles[name]
+	if profile == nil {
+		return "", credentials.ErrNotFound
+	}
+	store, err := credentialStore(profile.CredentialStore, config)
+	if err != nil {
+		return "", err
+	}
+	return store.Get(name)
+}
+
+// saveProfileAPIKey stores apiKey for a profile. With storeName "auto" it
+// tries the keyring, then the encrypted file, then the plaintext config file,
+// and returns the name of the store that was used. The caller must still
+// call SaveConfig to record the choice.
+func saveProfileAPIKey(config *Config, name, apiKey, storeName string) (string, error) {
+	profile := config.Profiles[name]
+	if profile == nil {
+		profile = &Profile{}
+		config.Profiles[name] = profile
+	}
+	previous := profile.CredentialStore
+
+	candidates := []string{storeName}
+	if storeName == "auto" {
+		candidates = []string{credentials.StoreKeyring, credentials.StoreEncrypted, credentials.StoreFile}
+	}
+
+	var lastErr error
+	for i, candidate := range candidates {
+		store, err := credentialStore(candidate, config)
+		if err != nil {
+			return "", err
+		}
+		if err := store.Set(name, apiKey); err != nil {
+			lastErr = err
+			if i+1 < len(candidates) {
+				fmt.Fprintf(os.Stderr, "Note: could not use %s credential store (%v), trying %s\n", candidate, err, candidates[i+1])
+			}
+			continue
+		}
+
+		profile.CredentialStore = candidate
+		if candidate == credentials.StoreFile {
+			profile.CredentialStore = ""
+		}
+		// Remove the key from the store it used to be in
+		if previous != profile.CredentialStore {
+			if oldStore, err := credentialStore(previous, config); err == nil {
+				_ = oldStore.Delete(name)
+			}
+		}
+		return candidate, nil
+	}
+	return "", lastErr
+}
+
+// deleteProfileAPIKey removes the stored API key for a profile
+func deleteProfileAPIKey(config *Config, name string) error {
+	profile := config.Profiles[name]
+	if profile == nil {
+		return nil
+	}
+	store, err := credentialStore(profile.CredentialStore, config)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(name); err != nil && !errors.Is(err, credentials.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+var (
+	passphraseOnce  sync.Once
+	passphraseValue string
+	passphraseErr   error
+)
+
+// credentialsPassphrase returns the passphrase for the encrypted credentials
+// file from HYPERBOLIC_CREDENTIALS_PASSPHRASE or an interactive prompt. It
+// is asked for at most once per command. With confirm, as when the file is
+// being created, the prompt asks for it twice.
+func credentialsPassphrase(confirm bool) (string, error) {
+	passphraseOnce.Do(func() {
+		if envPassphrase := os.Getenv("HYPERBOLIC_CREDENTIALS_PASSPHRASE"); envPassphrase != "" {
+			passphraseValue = envPassphrase
+			return
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			passphraseErr = errors.New("set HYPERBOLIC_CREDENTIALS_PASSPHRASE to unlock the encrypted credentials file")
+			return
+		}
+		passphraseValue, passphraseErr = promptPassphrase(readPassword, confirm)
+	})
+	return passphraseValue, passphraseErr
+}
+
+// promptPassphrase asks for the passphrase with read. With confirm it is
+// asked for again and both entries must match.
+func promptPassphrase(read func(prompt string) (string, error), confirm bool) (string, error) {
+	if !confirm {
+		return read("Credentials passphrase: ")
+	}
+	passphrase, err := read("New credentials passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	again, err := read("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// readPassword prompts on stderr and reads a line from the terminal without
+// echoing it
+func readPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("error reading passphrase: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// configFileStore keeps API keys in plaintext in the profiles of config.json
+type configFileStore struct {
+	config *Config
+}
+
+func (s *configFileStore) Name() string {
+	return credentials.StoreFile
+}
+
+func (s *configFileStore) Get(profile string) (string, error) {
+	p := s.config.Profiles[profile]
+	if p == nil || p.APIKey == "" {
+		return "", credentials.ErrNotFound
+	}
+	return p.APIKey, nil
+}
+
+func (s *configFileStore) Set(profile, apiKey string) error {
+	p := s.config.Profiles[profile]
+	if p == nil {
+		p = &Profile{}
+		s.config.Profiles[profile] = p
+	}
+	p.APIKey = apiKey
+	return nil
+}
+
+func (s *configFileStore) Delete(profile string) error {
+	p := s.config.Profiles[profile]
+	if p == nil || p.APIKey == "" {
+		return credentials.ErrNotFound
+	}
+	p.APIKey = ""
+	return nil
+}
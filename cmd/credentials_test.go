@@ -0,0 +1,156 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/credentials"
+)
+
+// unavailableKeyring is a keyring whose secret service cannot be reached
+type unavailableKeyring struct{}
+
+func (unavailableKeyring) Get(service, user string) (string, error) {
+	return "", errors.New("no secret service")
+}
+
+func (unavailableKeyring) Set(service, user, password string) error {
+	return errors.New("no secret service")
+}
+
+func (unavailableKeyring) Delete(service, user string) error {
+	return errors.New("no secret service")
+}
+
+// useCredentialStores points the credential stores at a temporary home
+// directory, keyring and passphrase for one test
+func useCredentialStores(t *testing.T, keyring credentials.Keyring, passphrase string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("HYPERBOLIC_CREDENTIALS_PASSPHRASE", passphrase)
+
+	// Never prompt, even when the tests are run from a terminal
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = devNull
+
+	previous := keyringBackend
+	keyringBackend = keyring
+	passphraseOnce = sync.Once{}
+	t.Cleanup(func() {
+		keyringBackend = previous
+		passphraseOnce = sync.Once{}
+		os.Stdin = stdin
+		devNull.Close()
+	})
+	return home
+}
+
+func TestSaveProfileAPIKeyAuto(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyring    credentials.Keyring
+		passphrase string
+		want       string
+	}{
+		{"keyring", &credentials.MemoryKeyring{}, "", credentials.StoreKeyring},
+		{"encrypted without a keyring", unavailableKeyring{}, "correct horse", credentials.StoreEncrypted},
+		// Without a terminal or HYPERBOLIC_CREDENTIALS_PASSPHRASE there is no
+		// passphrase for the encrypted file
+		{"file without a keyring or passphrase", unavailableKeyring{}, "", credentials.StoreFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := useCredentialStores(t, tt.keyring, tt.passphrase)
+			config := &Config{Profiles: map[string]*Profile{}}
+
+			used, err := saveProfileAPIKey(config, "default", "secret-key", "auto")
+			if err != nil {
+				t.Fatalf("saveProfileAPIKey: %v", err)
+			}
+			if used != tt.want {
+				t.Errorf("used the %s store, want %s", used, tt.want)
+			}
+
+			// The key reads back from the store recorded in the profile
+			passphraseOnce = sync.Once{}
+			got, err := profileAPIKey(config, "default")
+			if err != nil || got != "secret-key" {
+				t.Errorf("profileAPIKey = %q, %v", got, err)
+			}
+
+			// Only the file store puts the key in config.json
+			if apiKey := config.Profiles["default"].APIKey; (tt.want == credentials.StoreFile) != (apiKey != "") {
+				t.Errorf("profile API key in config is %q with the %s store", apiKey, used)
+			}
+			_, err = os.Stat(filepath.Join(home, ".hyperbolic", "credentials.enc"))
+			if (tt.want == credentials.StoreEncrypted) != (err == nil) {
+				t.Errorf("credentials.enc exists: %v, with the %s store", err == nil, used)
+			}
+		})
+	}
+}
+
+func TestSaveProfileAPIKeyMovesKey(t *testing.T) {
+	keyring := &credentials.MemoryKeyring{}
+	useCredentialStores(t, keyring, "")
+	config := &Config{Profiles: map[string]*Profile{"default": {APIKey: "old-key"}}}
+
+	if _, err := saveProfileAPIKey(config, "default", "new-key", credentials.StoreKeyring); err != nil {
+		t.Fatalf("saveProfileAPIKey: %v", err)
+	}
+	if apiKey := config.Profiles["default"].APIKey; apiKey != "" {
+		t.Errorf("expected the plaintext key to be removed from config, got %q", apiKey)
+	}
+	if got, err := keyring.Get(credentials.KeyringService, "default"); err != nil || got != "new-key" {
+		t.Errorf("keyring holds %q, %v", got, err)
+	}
+}
+
+func TestPromptPassphrase(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		confirm bool
+		want    string
+		wantErr bool
+		prompts int
+	}{
+		{"existing file", []string{"correct horse"}, false, "correct horse", false, 1},
+		{"new file", []string{"correct horse", "correct horse"}, true, "correct horse", false, 2},
+		{"new file with a typo", []string{"correct horse", "correct hrose"}, true, "", true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompts := 0
+			read := func(prompt string) (string, error) {
+				if prompts >= len(tt.entries) {
+					return "", errors.New("prompted too many times")
+				}
+				prompts++
+				return tt.entries[prompts-1], nil
+			}
+
+			got, err := promptPassphrase(read, tt.confirm)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("promptPassphrase = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+			if prompts != tt.prompts {
+				t.Errorf("prompted %d times, want %d", prompts, tt.prompts)
+			}
+		})
+	}
+}
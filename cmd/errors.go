@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+// Exit codes returned by the CLI. These are part of the CLI's public
+// interface and are documented in the README; do not renumber them.
+const (
+	ExitOK                  = 0
+	ExitError               = 1 // unclassified failure
+	ExitUsage               = 2 // invalid flags or arguments
+	ExitAuth                = 3 // API key missing, invalid or unauthorized
+	ExitNotFound            = 4 // instance or resource not found
+	ExitInsufficientBalance = 5 // not enough credits for the request
+	ExitAPIClientError      = 6 // API rejected the request (4xx)
+	ExitAPIServerError      = 7 // API failed to handle the request (5xx)
+	ExitNetwork             = 8 // API could not be reached
+	ExitTimeout             = 9 // gave up waiting, e.g. for an instance to start
+)
+
+// AuthError is returned when no usable API key is configured
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication error: %v\n%s", e.Err, authHint)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// NotFoundError is returned when an instance or other resource does not exist
+type NotFoundError struct {
+	Kind string
+	ID   string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s '%s' not found", e.Kind, e.ID)
+}
+
+// TimeoutError is returned when a command gives up waiting for something
+type TimeoutError struct {
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// ExitStatusError is returned when a program run by a command, such as ssh,
+// exits unsuccessfully. The CLI exits with the same status without printing
+// anything, as the program has reported the problem itself. The status is
+// not mapped into a reserved range, so it may collide with the CLI's own
+// exit codes; ssh exits with the remote command's status.
+type ExitStatusError struct {
+	Status int
+}
+
+func (e *ExitStatusError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Status)
+}
+
+// UsageError is returned for invalid flags or arguments
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// usageErrorf returns a UsageError with a formatted message
+func usageErrorf(format string, a ...interface{}) error {
+	return &UsageError{Err: fmt.Errorf(format, a...)}
+}
+
+// exitCode maps an error returned by a command to the process exit code
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var usageErr *UsageError
+	var authErr *AuthError
+	var notFoundErr *NotFoundError
+	var apiErr *hyperbolic.APIError
+	var networkErr *hyperbolic.NetworkError
+	var timeoutErr *TimeoutError
+	var statusErr *ExitStatusError
+
+	switch {
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.As(err, &authErr):
+		return ExitAuth
+	case errors.As(err, &notFoundErr):
+		return ExitNotFound
+	case errors.As(err, &timeoutErr):
+		return ExitTimeout
+	case errors.As(err, &statusErr):
+		return statusErr.Status
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.IsUnauthorized():
+			return ExitAuth
+		case apiErr.IsInsufficientBalance():
+			return ExitInsufficientBalance
+		case apiErr.IsNotFound():
+			return ExitNotFound
+		case apiErr.IsServerError():
+			return ExitAPIServerError
+		default:
+			return ExitAPIClientError
+		}
+	case errors.As(err, &networkErr):
+		return ExitNetwork
+	}
+
+	// Cobra does not export a type for its own argument and required-flag
+	// validation errors, so recognise them by message.
+	message := err.Error()
+	if strings.HasPrefix(message, "required flag(s)") ||
+		strings.HasPrefix(message, "unknown command") ||
+		strings.HasPrefix(message, "accepts ") ||
+		strings.HasPrefix(message, "requires at least") {
+		return ExitUsage
+	}
+
+	return ExitError
+}
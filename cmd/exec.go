@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec <instance-id> -- <command> [args...]",
+	Short: "Run a command on every node of an instance.",
+	Long: `Run a command over SSH on every node of a rented instance in parallel, such as the nodes of a multi-node bare-metal rental.
+
+Output is streamed as it arrives, with each line prefixed by its node number. The command exits non-zero if the command failed on any node. With --fail-fast, the first failure stops the command on the other nodes, and nodes that have not started yet (see --max-parallel) are skipped.
+
+SSH runs in batch mode, so key-based authentication must be set up and passwords are not prompted for. Nor is trusting a new host key: connect to each node once with 'hyperbolic ssh <instance-id> --node N' to check and record its key, or pass --accept-new-host-keys to trust nodes not yet in known_hosts.
+
+EXAMPLES:
+  hyperbolic exec 1234 -- nvidia-smi -L
+  hyperbolic exec 1234 --nodes 1,3 -- 'sudo apt-get update && sudo apt-get install -y htop'
+  hyperbolic exec 1234 --max-parallel 2 --fail-fast -- ./upgrade-driver.sh
+  hyperbolic exec 1234 --max-parallel 2 --identity ~/.ssh/hyperbolic_ed25519 -- ./setup.sh`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		instanceID := args[0]
+		command := args[1:]
+		if len(command) == 0 || cmd.ArgsLenAtDash() != 1 {
+			return usageErrorf("the command to run must follow '--', e.g. hyperbolic exec %s -- nvidia-smi", instanceID)
+		}
+
+		identity, _ := cmd.Flags().GetString("identity")
+		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		batchOptions := batchSSHArgs(cmd)
+		limit, err := maxParallel(cmd)
+		if err != nil {
+			return err
+		}
+
+		path, err := sshPath()
+		if err != nil {
+			return err
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		instance, err := findInstance(cmd.Context(), client, instanceID)
+		if err != nil {
+			return err
+		}
+
+		targets, err := selectNodeTargets(cmd, instance)
+		if err != nil {
+			return err
+		}
+
+		results := runOnNodes(cmd.Context(), targets, limit, failFast, os.Stdout, os.Stderr, func(ctx context.Context, target nodeTarget, stdout, stderr io.Writer) error {
+			// "--" stops ssh reading options from the command
+			args := append(append([]string{}, batchOptions...), sshArgs(target.Endpoint, identity, append([]string{"--"}, command...))...)
+			ssh := exec.CommandContext(ctx, path, args...)
+			ssh.Stdout = stdout
+			ssh.Stderr = stderr
+			return ssh.Run()
+		})
+
+		return nodeResultsError(results, os.Stderr, "command")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	addNodeFlags(execCmd)
+	addHostKeyFlag(execCmd)
+	execCmd.Flags().StringP("identity", "i", "", "Private key file to authenticate with (passed to ssh -i)")
+	execCmd.Flags().Bool("fail-fast", false, "Stop the command on every node as soon as it fails on one, and start it on no more nodes")
+}
@@ -0,0 +1,206 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeSSH is put on PATH as ssh. It logs the host it was asked to connect
+// to, prints a line to stdout and one to stderr, and exits with the status
+// given for that host in FAKE_SSH_FAIL, e.g. "203.0.113.3=3". The host in
+// FAKE_SSH_HANG never finishes, and the others wait until it has printed.
+const fakeSSH = `#!/bin/sh
+for arg; do
+	case "$arg" in
+	*@*) host=${arg#*@} ;;
+	esac
+done
+echo "$host" >> "$FAKE_SSH_LOG"
+echo "hello from $host"
+echo "warning from $host" >&2
+if [ "$host" = "$FAKE_SSH_HANG" ]; then
+	touch "$FAKE_SSH_LOG.hanging"
+	exec sleep 60
+fi
+if [ -n "$FAKE_SSH_HANG" ]; then
+	while [ ! -e "$FAKE_SSH_LOG.hanging" ]; do sleep 0.01; done
+fi
+for failure in $FAKE_SSH_FAIL; do
+	if [ "${failure%%=*}" = "$host" ]; then
+		exit "${failure#*=}"
+	fi
+done
+`
+
+// useFakeSSH puts fakeSSH first on PATH, with the hosts in fail failing and
+// hang hanging, and returns a function listing the hosts it ran against
+func useFakeSSH(t *testing.T, hang string, fail ...string) func() []string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ssh is a shell script")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(fakeSSH), 0755); err != nil {
+		t.Fatal(err)
+	}
+	log := filepath.Join(dir, "ssh.log")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_SSH_LOG", log)
+	t.Setenv("FAKE_SSH_FAIL", strings.Join(fail, " "))
+	t.Setenv("FAKE_SSH_HANG", hang)
+
+	return func() []string {
+		data, _ := os.ReadFile(log)
+		hosts := strings.Fields(string(data))
+		slices.Sort(hosts)
+		return hosts
+	}
+}
+
+func TestExec(t *testing.T) {
+	// Rental 1002 has two nodes, 203.0.113.3 and 203.0.113.4
+	const node1, node2 = "203.0.113.3", "203.0.113.4"
+
+	tests := []struct {
+		name  string
+		args  []string
+		hang  string
+		fail  []string
+		ran   []string
+		error string
+	}{
+		{
+			name: "every node",
+			args: []string{"exec", "1002", "--", "hostname"},
+			ran:  []string{node1, node2},
+		},
+		{
+			name: "chosen nodes",
+			args: []string{"exec", "1002", "--nodes", "2", "--", "hostname"},
+			ran:  []string{node2},
+		},
+		{
+			name:  "one node fails",
+			args:  []string{"exec", "1002", "--", "hostname"},
+			fail:  []string{node1 + "=3"},
+			ran:   []string{node1, node2},
+			error: "command failed on 1 of 2 node(s)",
+		},
+		{
+			name:  "every node fails",
+			args:  []string{"exec", "1002", "--", "hostname"},
+			fail:  []string{node1 + "=3", node2 + "=255"},
+			ran:   []string{node1, node2},
+			error: "command failed on 2 of 2 node(s)",
+		},
+		{
+			name:  "fail fast skips nodes not started",
+			args:  []string{"exec", "1002", "--max-parallel", "1", "--fail-fast", "--", "hostname"},
+			fail:  []string{node1 + "=3"},
+			ran:   []string{node1},
+			error: "command failed on 1 of 2 node(s); --fail-fast stopped the other 1",
+		},
+		{
+			name:  "fail fast stops running nodes",
+			args:  []string{"exec", "1002", "--fail-fast", "--", "hostname"},
+			hang:  node2,
+			fail:  []string{node1 + "=3"},
+			ran:   []string{node1, node2},
+			error: "command failed on 1 of 2 node(s); --fail-fast stopped the other 1",
+		},
+		{
+			name:  "fail fast after the last node",
+			args:  []string{"exec", "1002", "--max-parallel", "1", "--fail-fast", "--", "hostname"},
+			fail:  []string{node2 + "=3"},
+			ran:   []string{node1, node2},
+			error: "command failed on 1 of 2 node(s)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newGoldenServer(t)
+			ran := useFakeSSH(t, tt.hang, tt.fail...)
+			stderr := captureStderr(t)
+
+			output, err := executeCLI(t, tt.args...)
+			if got := ran(); !slices.Equal(got, tt.ran) {
+				t.Errorf("ssh ran against %v, want %v", got, tt.ran)
+			}
+
+			// Every node's output is prefixed by its number
+			for _, host := range tt.ran {
+				number := "1"
+				if host == node2 {
+					number = "2"
+				}
+				if line := "[node " + number + "] hello from " + host + "\n"; !strings.Contains(output, line) {
+					t.Errorf("output %q does not contain %q", output, line)
+				}
+				if line := "[node " + number + "] warning from " + host + "\n"; !strings.Contains(stderr(), line) {
+					t.Errorf("stderr %q does not contain %q", stderr(), line)
+				}
+			}
+
+			if tt.error == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			// A summary of every node follows the output
+			if !strings.Contains(stderr(), "\nnode 1 (ubuntu@"+node1+"): ") {
+				t.Errorf("stderr %q has no summary", stderr())
+			}
+			if err == nil || err.Error() != tt.error {
+				t.Fatalf("got error %v, want %q", err, tt.error)
+			}
+			// Remote exit statuses are summarised, not passed through
+			if code := exitCode(err); code != ExitError {
+				t.Errorf("exit code %d, want %d", code, ExitError)
+			}
+		})
+	}
+}
+
+// captureStderr sends stderr to a file for the rest of the test, and returns
+// a function reading what was written
+func captureStderr(t *testing.T) func() string {
+	t.Helper()
+	file, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = file
+	t.Cleanup(func() {
+		os.Stderr = stderr
+		file.Close()
+	})
+	return func() string {
+		data, _ := os.ReadFile(file.Name())
+		return string(data)
+	}
+}
+
+func TestBatchSSHArgs(t *testing.T) {
+	resetFlags(rootCmd)
+	t.Cleanup(func() { resetFlags(rootCmd) })
+
+	// Host keys are only trusted on first use when asked for
+	if args := strings.Join(batchSSHArgs(execCmd), " "); args != "-o BatchMode=yes" {
+		t.Errorf("got %q without --accept-new-host-keys", args)
+	}
+	if err := execCmd.Flags().Set("accept-new-host-keys", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if args := strings.Join(batchSSHArgs(execCmd), " "); args != "-o BatchMode=yes -o StrictHostKeyChecking=accept-new" {
+		t.Errorf("got %q with --accept-new-host-keys", args)
+	}
+}
@@ -0,0 +1,393 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic/mock"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// timestampPattern matches the RFC 3339 times the mock server stamps on
+// rentals when they are made
+var timestampPattern = regexp.MustCompile(`"\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})"`)
+
+// goldenSpec is the spec file used for 'plan'
+const goldenSpec = `name: golden
+rentals:
+  - name: workers
+    type: spot
+    count: 2
+    gpuModel: RTX-4090
+    gpuCount: 1
+    region: eu-central
+  - name: trainer
+    type: virtual-machine
+    gpuCount: 8
+  - name: unplaceable
+    type: spot
+    gpuModel: A100
+    gpuCount: 4
+`
+
+// newGoldenServer starts the mock API with one spot and one two-node
+// bare-metal rental, and points the CLI at it with a fresh home directory
+func newGoldenServer(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(mock.NewServer())
+	t.Cleanup(server.Close)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("HYPERBOLIC_API_URL", server.URL)
+	t.Setenv("HYPERBOLIC_API_KEY", "golden-key")
+	t.Setenv("HYPERBOLIC_PROFILE", "")
+
+	ctx := context.Background()
+	client := hyperbolic.NewClient("golden-key", hyperbolic.WithBaseURL(server.URL))
+	if _, err := client.RentSpotInstance(ctx, hyperbolic.RentRequest{ClusterName: "mock-cluster-eu", NodeName: "mock-4090-2", GpuCount: 1}); err != nil {
+		t.Fatalf("renting spot instance: %v", err)
+	}
+	if _, err := client.RentBareMetal(ctx, 16, "infiniband"); err != nil {
+		t.Fatalf("renting bare metal: %v", err)
+	}
+
+	spec := filepath.Join(home, "golden.yaml")
+	if err := os.WriteFile(spec, []byte(goldenSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// runCLI runs the CLI with args and returns what it printed to stdout
+func runCLI(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := executeCLI(t, args...)
+	if err != nil {
+		t.Fatalf("hyperbolic %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return output
+}
+
+// executeCLI is runCLI for commands that are expected to fail
+func executeCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	resetFlags(rootCmd)
+	t.Cleanup(func() { resetFlags(rootCmd) })
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	output := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- data
+	}()
+
+	rootCmd.SetArgs(args)
+	runErr := rootCmd.ExecuteContext(context.Background())
+
+	os.Stdout = stdout
+	writer.Close()
+	data := <-output
+	reader.Close()
+	return string(data), runErr
+}
+
+// resetFlags restores every flag of cmd and its subcommands to its default,
+// as cobra keeps flag values between executions
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			_ = slice.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+func TestGoldenJSON(t *testing.T) {
+	spec := newGoldenServer(t)
+
+	tests := []struct {
+		golden string
+		schema string
+		args   []string
+	}{
+		{"instances.json", "instance-list.schema.json", []string{"instances", "-o", "json"}},
+		{"instance.json", "instance.schema.json", []string{"instances", "1002", "-o", "json"}},
+		{"spot.json", "marketplace-node-list.schema.json", []string{"spot", "-o", "json"}},
+		{"ondemand.json", "ondemand-offer-list.schema.json", []string{"ondemand", "-o", "json"}},
+		{"account.json", "account.schema.json", []string{"account", "-o", "json"}},
+		{"cluster-env.json", "cluster-env.schema.json", []string{"cluster", "env", "1002", "-o", "json"}},
+		{"plan.json", "rental-plan.schema.json", []string{"plan", "-f", spec, "-o", "json"}},
+	}
+
+	schemas := newSchemaValidator(filepath.Join("..", "schema", "v1"))
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got := timestampPattern.ReplaceAllString(runCLI(t, tt.args...), `"2025-01-01T00:00:00Z"`)
+
+			path := filepath.Join("testdata", tt.golden)
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run 'go test ./cmd -run TestGoldenJSON -update' to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("hyperbolic %s output differs from %s:\n%s", strings.Join(tt.args, " "), path, got)
+			}
+
+			for _, problem := range schemas.validate(tt.schema, []byte(got)) {
+				t.Errorf("%s does not match %s: %s", tt.golden, tt.schema, problem)
+			}
+		})
+	}
+}
+
+// schemaValidator checks documents against the JSON Schema files in
+// schema/v1. It supports the keywords those files use.
+type schemaValidator struct {
+	dir     string
+	schemas map[string]map[string]interface{}
+}
+
+func newSchemaValidator(dir string) *schemaValidator {
+	return &schemaValidator{dir: dir, schemas: map[string]map[string]interface{}{}}
+}
+
+// load reads a schema file
+func (v *schemaValidator) load(name string) (map[string]interface{}, error) {
+	if schema, ok := v.schemas[name]; ok {
+		return schema, nil
+	}
+	data, err := os.ReadFile(filepath.Join(v.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", name, err)
+	}
+	v.schemas[name] = schema
+	return schema, nil
+}
+
+// validate returns the ways document does not match the named schema
+func (v *schemaValidator) validate(name string, document []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []string{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	var problems []string
+	v.check(name, map[string]interface{}{"$ref": name}, value, "$", &problems)
+	return problems
+}
+
+// check validates value against schema, which is part of the file named
+// file, and appends any problems found
+func (v *schemaValidator) check(file string, schema map[string]interface{}, value interface{}, path string, problems *[]string) {
+	fail := func(format string, a ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, a...))
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		refFile, pointer, _ := strings.Cut(ref, "#")
+		if refFile == "" {
+			refFile = file
+		}
+		target, err := v.load(refFile)
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		for _, part := range strings.Split(strings.Trim(pointer, "/"), "/") {
+			if part == "" {
+				continue
+			}
+			next, ok := target[part].(map[string]interface{})
+			if !ok {
+				fail("unresolved $ref %s in %s", ref, file)
+				return
+			}
+			target = next
+		}
+		v.check(refFile, target, value, path, problems)
+	}
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			v.check(file, sub.(map[string]interface{}), value, path, problems)
+		}
+	}
+
+	if want, ok := schema["type"].(string); ok && jsonType(value, want) != want {
+		fail("expected %s, got %s", want, jsonType(value, want))
+		return
+	}
+	if want, ok := schema["const"]; ok && !sameJSON(value, want) {
+		fail("expected %v, got %v", want, value)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, want := range enum {
+			found = found || sameJSON(value, want)
+		}
+		if !found {
+			fail("%v is not one of %v", value, enum)
+		}
+	}
+
+	if number, ok := value.(json.Number); ok {
+		n, _ := number.Float64()
+		if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+			fail("%v is below the minimum %v", n, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && n > maximum {
+			fail("%v is above the maximum %v", n, maximum)
+		}
+	}
+	if text, ok := value.(string); ok {
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(text) {
+			fail("%q does not match %s", text, pattern)
+		}
+		if schema["format"] == "date-time" && !timestampPattern.MatchString(`"`+text+`"`) {
+			fail("%q is not a date-time", text)
+		}
+	}
+
+	if object, ok := value.(map[string]interface{}); ok {
+		for _, key := range asStrings(schema["required"]) {
+			if _, ok := object[key]; !ok {
+				fail("missing required property %q", key)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, child := range object {
+			if sub, ok := properties[key].(map[string]interface{}); ok {
+				v.check(file, sub, child, path+"."+key, problems)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					fail("unexpected property %q", key)
+				}
+			case map[string]interface{}:
+				v.check(file, additional, child, path+"."+key, problems)
+			}
+		}
+	}
+
+	if array, ok := value.([]interface{}); ok {
+		if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(array)) > maxItems {
+			fail("%d items, at most %v allowed", len(array), maxItems)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range array {
+				v.check(file, items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+// jsonType returns the JSON Schema type of value. Whole numbers are
+// "integer", or "number" when want is "number".
+func jsonType(value interface{}, want string) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number:
+		if _, err := v.Int64(); err == nil && want != "number" {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// sameJSON reports whether two decoded JSON values are equal
+func sameJSON(a, b interface{}) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return bytes.Equal(aJSON, bJSON)
+}
+
+func asStrings(value interface{}) []string {
+	var values []string
+	if list, ok := value.([]interface{}); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+func TestSchemaValidator(t *testing.T) {
+	schemas := newSchemaValidator(filepath.Join("..", "schema", "v1"))
+	tests := []struct {
+		name     string
+		schema   string
+		document string
+		problem  string
+	}{
+		{"wrong kind", "account.schema.json", `{"apiVersion":"cli.hyperbolic.xyz/v1","kind":"Instance","user":{},"credits":1,"balanceUSD":0.01}`, "expected"},
+		{"missing items", "instance-list.schema.json", `{"apiVersion":"cli.hyperbolic.xyz/v1","kind":"InstanceList"}`, `missing required property "items"`},
+		{"bad enum in a $ref", "instance-list.schema.json", `{"apiVersion":"cli.hyperbolic.xyz/v1","kind":"InstanceList","items":[{"id":"1","type":"cloud","status":"running","gpu":{"model":"","count":1,"perNode":1},"nodes":[],"ports":[],"pricePerHourUSD":1}]}`, "is not one of"},
+		{"fractional integer", "ondemand-offer-list.schema.json", `{"apiVersion":"cli.hyperbolic.xyz/v1","kind":"OnDemandOfferList","items":[{"type":"virtual-machine","gpuModel":"H100","gpuCounts":[1.5],"pricePerGPUHourUSD":1}]}`, "expected integer"},
+		{"below minimum", "cluster-env.schema.json", `{"apiVersion":"cli.hyperbolic.xyz/v1","kind":"ClusterEnv","instanceId":"1","networkType":"ethernet","masterAddr":"10.0.0.1","masterPort":0,"nnodes":1,"nprocPerNode":8,"worldSize":8,"nodes":[],"env":{}}`, "below the minimum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := schemas.validate(tt.schema, []byte(tt.document))
+			if !strings.Contains(strings.Join(problems, "\n"), tt.problem) {
+				t.Errorf("expected a problem containing %q, got %q", tt.problem, problems)
+			}
+		})
+	}
+}
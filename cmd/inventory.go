@@ -0,0 +1,288 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+// inventoryAllGroup holds every host of the inventory
+const inventoryAllGroup = "hyperbolic"
+
+// Formats of 'hyperbolic inventory'
+const (
+	inventoryFormatJSON = "json"
+	inventoryFormatINI  = "ini"
+	inventoryFormatYAML = "yaml"
+)
+
+// inventoryCmd represents the inventory command
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Export your instances as an Ansible inventory.",
+	Long: `Print an Ansible inventory of your running instances, with one host per node named as in 'hyperbolic ssh-config'. Hosts are grouped by:
+
+  hyperbolic           every host
+  rental_<id>          the nodes of one rental
+  spot, virtual_machine, bare_metal
+  gpu_<model>          e.g. gpu_h100_80gb_hbm3
+  network_<type>       network_ethernet or network_infiniband
+
+ansible_host, ansible_port and ansible_user come from each node's SSH details, and hyperbolic_* variables describe the instance.
+
+The default JSON output is Ansible's dynamic inventory format, and --list and --host let the CLI act as an inventory script:
+
+  printf '#!/bin/sh\nexec hyperbolic inventory "$@"\n' > hyperbolic.sh && chmod +x hyperbolic.sh
+  ansible -i hyperbolic.sh bare_metal -m ping
+
+EXAMPLES:
+  hyperbolic inventory --format ini > inventory.ini
+  hyperbolic inventory --format yaml > inventory.yaml
+  hyperbolic inventory --host hyperbolic-1234`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inventoryFormat, _ := cmd.Flags().GetString("format")
+		list, _ := cmd.Flags().GetBool("list")
+		host, _ := cmd.Flags().GetString("host")
+
+		if outputFormat != "" {
+			return usageErrorf("inventory does not support -o; use --format %s|%s|%s", inventoryFormatJSON, inventoryFormatINI, inventoryFormatYAML)
+		}
+		switch inventoryFormat {
+		case inventoryFormatJSON, inventoryFormatINI, inventoryFormatYAML:
+		default:
+			return usageErrorf("invalid --format '%s'. Must be one of: %s, %s, %s", inventoryFormat, inventoryFormatJSON, inventoryFormatINI, inventoryFormatYAML)
+		}
+		if list && cmd.Flags().Changed("host") {
+			return usageErrorf("--list and --host cannot be combined")
+		}
+		// Ansible reads inventory scripts as JSON
+		if (list || cmd.Flags().Changed("host")) && inventoryFormat != inventoryFormatJSON {
+			return usageErrorf("--list and --host always print JSON and cannot be combined with --format %s", inventoryFormat)
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		instances, err := client.ListInstances(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("error fetching instances: %w", err)
+		}
+		inventory := newInventory(instances)
+
+		jsonFormat, err := printer.ParseFormat(printer.FormatJSON)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("host") {
+			// Unknown hosts have no variables, as Ansible expects
+			vars := map[string]interface{}{}
+			if h, ok := inventory.host(host); ok {
+				vars = h.Vars
+			}
+			return jsonFormat.Print(os.Stdout, vars, nil)
+		}
+
+		switch inventoryFormat {
+		case inventoryFormatINI:
+			inventory.writeINI(os.Stdout)
+			return nil
+		case inventoryFormatYAML:
+			yamlFormat, err := printer.ParseFormat(printer.FormatYAML)
+			if err != nil {
+				return err
+			}
+			return yamlFormat.Print(os.Stdout, inventory.yaml(), nil)
+		}
+		return jsonFormat.Print(os.Stdout, inventory.dynamic(), nil)
+	},
+}
+
+// inventory is an Ansible inventory of instance nodes
+type inventory struct {
+	Hosts []inventoryHost
+	// Groups maps group names to the names of their hosts
+	Groups map[string][]string
+}
+
+// inventoryHost is one node in an inventory
+type inventoryHost struct {
+	Name string
+	Vars map[string]interface{}
+}
+
+// newInventory returns the inventory of the nodes of instances that can be
+// reached over SSH. Instances that have ended are left out.
+func newInventory(instances []hyperbolic.Instance) inventory {
+	inv := inventory{Groups: map[string][]string{}}
+	for _, instance := range instances {
+		if instance.HasEnded() {
+			continue
+		}
+		perNode := instance.Type == hyperbolic.InstanceTypeBareMetal || len(instance.Nodes) > 1
+		for i, node := range instance.Nodes {
+			if node.SSH == nil {
+				continue
+			}
+
+			name := sshHostAlias(instance, 0)
+			if perNode {
+				name = sshHostAlias(instance, i+1)
+			}
+			vars := map[string]interface{}{
+				"ansible_host":             node.SSH.Host,
+				"ansible_port":             node.SSH.Port,
+				"hyperbolic_instance_id":   instance.ID,
+				"hyperbolic_instance_type": string(instance.Type),
+				"hyperbolic_node":          i + 1,
+				"hyperbolic_status":        instance.Status,
+				"hyperbolic_gpu_model":     instance.GPUModel,
+				"hyperbolic_gpu_count":     instance.GPUsPerNode,
+			}
+			if node.SSH.User != "" {
+				vars["ansible_user"] = node.SSH.User
+			}
+			if node.PrivateIP != "" {
+				vars["hyperbolic_private_ip"] = node.PrivateIP
+			}
+			if instance.NetworkType != "" {
+				vars["hyperbolic_network_type"] = instance.NetworkType
+			}
+			inv.Hosts = append(inv.Hosts, inventoryHost{Name: name, Vars: vars})
+
+			groups := []string{
+				inventoryAllGroup,
+				"rental_" + ansibleGroupName(instance.ID),
+				ansibleGroupName(string(instance.Type)),
+			}
+			if instance.GPUModel != "" {
+				groups = append(groups, "gpu_"+ansibleGroupName(displayGPUModel(instance.GPUModel)))
+			}
+			if instance.NetworkType != "" {
+				groups = append(groups, "network_"+ansibleGroupName(instance.NetworkType))
+			}
+			for _, group := range groups {
+				inv.Groups[group] = append(inv.Groups[group], name)
+			}
+		}
+	}
+	return inv
+}
+
+// ansibleGroupName returns value as a valid Ansible group name: lowercase,
+// with anything other than letters, digits and '_' replaced by '_'
+func ansibleGroupName(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(value))
+}
+
+// host returns the host with the given name
+func (inv inventory) host(name string) (inventoryHost, bool) {
+	for _, host := range inv.Hosts {
+		if host.Name == name {
+			return host, true
+		}
+	}
+	return inventoryHost{}, false
+}
+
+// groupNames returns the group names in order, with the group of every host
+// first
+func (inv inventory) groupNames() []string {
+	names := make([]string, 0, len(inv.Groups))
+	for name := range inv.Groups {
+		if name != inventoryAllGroup {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := inv.Groups[inventoryAllGroup]; ok {
+		names = append([]string{inventoryAllGroup}, names...)
+	}
+	return names
+}
+
+// dynamic returns the inventory in Ansible's dynamic inventory JSON form,
+// with host variables under _meta so Ansible does not call --host per host
+func (inv inventory) dynamic() map[string]interface{} {
+	hostvars := map[string]interface{}{}
+	for _, host := range inv.Hosts {
+		hostvars[host.Name] = host.Vars
+	}
+	result := map[string]interface{}{
+		"_meta": map[string]interface{}{"hostvars": hostvars},
+	}
+	for name, hosts := range inv.Groups {
+		result[name] = map[string]interface{}{"hosts": hosts}
+	}
+	return result
+}
+
+// yaml returns the inventory in Ansible's YAML inventory form, with host
+// variables in the group of every host
+func (inv inventory) yaml() map[string]interface{} {
+	children := map[string]interface{}{}
+	for name, hosts := range inv.Groups {
+		groupHosts := map[string]interface{}{}
+		for _, host := range hosts {
+			groupHosts[host] = nil
+		}
+		children[name] = map[string]interface{}{"hosts": groupHosts}
+	}
+	allHosts := map[string]interface{}{}
+	for _, host := range inv.Hosts {
+		allHosts[host.Name] = host.Vars
+	}
+	children[inventoryAllGroup] = map[string]interface{}{"hosts": allHosts}
+	return map[string]interface{}{"all": map[string]interface{}{"children": children}}
+}
+
+// writeINI writes the inventory in Ansible's INI form, with host variables
+// in the group of every host
+func (inv inventory) writeINI(w io.Writer) {
+	for i, name := range inv.groupNames() {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "[%s]\n", name)
+		for _, hostName := range inv.Groups[name] {
+			if name != inventoryAllGroup {
+				fmt.Fprintln(w, hostName)
+				continue
+			}
+			host, _ := inv.host(hostName)
+			keys := make([]string, 0, len(host.Vars))
+			for key := range host.Vars {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			line := []string{hostName}
+			for _, key := range keys {
+				line = append(line, fmt.Sprintf("%s=%s", key, shellQuote(fmt.Sprint(host.Vars[key]))))
+			}
+			fmt.Fprintln(w, strings.Join(line, " "))
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+	inventoryCmd.Flags().String("format", inventoryFormatJSON, "Output format: json (dynamic inventory), ini or yaml")
+	inventoryCmd.Flags().Bool("list", false, "Print the whole inventory as JSON, as Ansible inventory scripts do")
+	inventoryCmd.Flags().String("host", "", "Print the variables of one host as JSON, as Ansible inventory scripts do")
+}
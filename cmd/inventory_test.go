@@ -0,0 +1,168 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"gopkg.in/yaml.v3"
+)
+
+// inventoryInstances returns a VM, a two-node bare-metal rental, a rental
+// without SSH details and one that has ended
+func inventoryInstances() []hyperbolic.Instance {
+	ended := hyperbolic.Instance{
+		ID:       "ended",
+		Type:     hyperbolic.InstanceTypeVirtualMachine,
+		Status:   "running",
+		GPUModel: "NVIDIA-H100-80GB-HBM3",
+		EndedAt:  time.Now(),
+		Nodes:    []hyperbolic.InstanceNode{{SSH: hyperbolic.ParseSSHCommand("ssh ubuntu@198.51.100.9")}},
+	}
+	return []hyperbolic.Instance{
+		{
+			ID:          "vm-1",
+			Type:        hyperbolic.InstanceTypeVirtualMachine,
+			Status:      "running",
+			GPUModel:    "NVIDIA-GeForce-RTX-4090",
+			GPUsPerNode: 1,
+			Nodes:       []hyperbolic.InstanceNode{{SSH: hyperbolic.ParseSSHCommand("ssh ubuntu@203.0.113.7 -p 31001")}},
+		},
+		{
+			ID:          "1002",
+			Type:        hyperbolic.InstanceTypeBareMetal,
+			Status:      "running",
+			GPUModel:    "NVIDIA-H100-80GB-HBM3",
+			GPUsPerNode: 8,
+			NetworkType: "InfiniBand",
+			Nodes: []hyperbolic.InstanceNode{
+				{PrivateIP: "10.10.0.1", SSH: hyperbolic.ParseSSHCommand("ssh ubuntu@203.0.113.3")},
+				{PrivateIP: "10.10.0.2", SSH: hyperbolic.ParseSSHCommand("ssh ubuntu@203.0.113.4")},
+			},
+		},
+		{ID: "starting", Type: hyperbolic.InstanceTypeVirtualMachine, Status: "starting", Nodes: []hyperbolic.InstanceNode{{}}},
+		ended,
+	}
+}
+
+func TestNewInventory(t *testing.T) {
+	inv := newInventory(inventoryInstances())
+
+	var names []string
+	for _, host := range inv.Hosts {
+		names = append(names, host.Name)
+	}
+	// Single-node VMs are named as in ssh-config, bare-metal nodes by number
+	wantNames := []string{"hyperbolic-vm-1", "hyperbolic-1002-node1", "hyperbolic-1002-node2"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("hosts = %v, want %v", names, wantNames)
+	}
+
+	wantGroups := map[string][]string{
+		"hyperbolic":         {"hyperbolic-vm-1", "hyperbolic-1002-node1", "hyperbolic-1002-node2"},
+		"virtual_machine":    {"hyperbolic-vm-1"},
+		"bare_metal":         {"hyperbolic-1002-node1", "hyperbolic-1002-node2"},
+		"rental_vm_1":        {"hyperbolic-vm-1"},
+		"rental_1002":        {"hyperbolic-1002-node1", "hyperbolic-1002-node2"},
+		"gpu_rtx_4090":       {"hyperbolic-vm-1"},
+		"gpu_h100_80gb_hbm3": {"hyperbolic-1002-node1", "hyperbolic-1002-node2"},
+		"network_infiniband": {"hyperbolic-1002-node1", "hyperbolic-1002-node2"},
+	}
+	if !reflect.DeepEqual(inv.Groups, wantGroups) {
+		t.Errorf("groups = %v, want %v", inv.Groups, wantGroups)
+	}
+
+	host, ok := inv.host("hyperbolic-1002-node2")
+	if !ok {
+		t.Fatal("host hyperbolic-1002-node2 is missing")
+	}
+	for key, want := range map[string]interface{}{
+		"ansible_host":          "203.0.113.4",
+		"ansible_port":          22,
+		"ansible_user":          "ubuntu",
+		"hyperbolic_node":       2,
+		"hyperbolic_private_ip": "10.10.0.2",
+	} {
+		if got := host.Vars[key]; got != want {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestInventoryINI(t *testing.T) {
+	inv := newInventory(inventoryInstances()[:1])
+
+	var ini strings.Builder
+	inv.writeINI(&ini)
+	want := `[hyperbolic]
+hyperbolic-vm-1 ansible_host=203.0.113.7 ansible_port=31001 ansible_user=ubuntu hyperbolic_gpu_count=1 hyperbolic_gpu_model=NVIDIA-GeForce-RTX-4090 hyperbolic_instance_id=vm-1 hyperbolic_instance_type=virtual-machine hyperbolic_node=1 hyperbolic_status=running
+
+[gpu_rtx_4090]
+hyperbolic-vm-1
+
+[rental_vm_1]
+hyperbolic-vm-1
+
+[virtual_machine]
+hyperbolic-vm-1
+`
+	if ini.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", ini.String(), want)
+	}
+}
+
+func TestInventoryYAML(t *testing.T) {
+	inv := newInventory(inventoryInstances())
+
+	data, err := yaml.Marshal(inv.yaml())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		All struct {
+			Children map[string]struct {
+				Hosts map[string]map[string]interface{} `yaml:"hosts"`
+			} `yaml:"children"`
+		} `yaml:"all"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	// Variables are set once, on the group of every host; other groups only
+	// list their hosts
+	all := doc.All.Children[inventoryAllGroup].Hosts
+	if len(all) != 3 || all["hyperbolic-vm-1"]["ansible_port"] != 31001 {
+		t.Errorf("group %s = %v", inventoryAllGroup, all)
+	}
+	bareMetal := doc.All.Children["bare_metal"].Hosts
+	if len(bareMetal) != 2 || bareMetal["hyperbolic-1002-node1"] != nil {
+		t.Errorf("group bare_metal = %v, want two hosts without variables", bareMetal)
+	}
+	if len(doc.All.Children) != len(inv.Groups) {
+		t.Errorf("got %d groups, want %d", len(doc.All.Children), len(inv.Groups))
+	}
+}
+
+func TestInventoryHost(t *testing.T) {
+	newGoldenServer(t)
+
+	var vars map[string]interface{}
+	if err := json.Unmarshal([]byte(runCLI(t, "inventory", "--host", "hyperbolic-1002-node2")), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if vars["ansible_host"] != "203.0.113.4" || vars["hyperbolic_instance_id"] != "1002" {
+		t.Errorf("got %v, want the variables of node 2 of rental 1002", vars)
+	}
+
+	// Ansible expects an empty object for hosts it does not know
+	if output := runCLI(t, "inventory", "--host", "hyperbolic-unknown"); strings.TrimSpace(output) != "{}" {
+		t.Errorf("unknown host: got %q, want {}", output)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic/mock"
+	"github.com/spf13/cobra"
+)
+
+// mockServerCmd represents the mock-server command
+var mockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "Run a local stand-in for the Hyperbolic API.",
+	Long: `Run a local HTTP server that serves canned marketplace, rental and billing responses for offline testing.
+
+Rentals created and terminated against the mock server are kept in memory until it exits. Point the CLI at it with --api-url or HYPERBOLIC_API_URL.`,
+	Example: `  hyperbolic mock-server --addr 127.0.0.1:8787
+  HYPERBOLIC_API_URL=http://127.0.0.1:8787 hyperbolic spot`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		apiKey, _ := cmd.Flags().GetString("require-key")
+		provisionDelay, _ := cmd.Flags().GetDuration("provision-delay")
+
+		server := mock.NewServer()
+		server.APIKey = apiKey
+		server.ProvisionDelay = provisionDelay
+
+		fmt.Printf("Mock Hyperbolic API listening on http://%s\n", addr)
+		if err := http.ListenAndServe(addr, server); err != nil {
+			return fmt.Errorf("error running mock server: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mockServerCmd)
+	mockServerCmd.Flags().String("addr", "127.0.0.1:8787", "Address to listen on")
+	mockServerCmd.Flags().String("require-key", "", "Only accept this API key (default: accept any non-empty key)")
+	mockServerCmd.Flags().Duration("provision-delay", 0, "How long new rentals report 'starting' before 'running'")
+}
@@ -0,0 +1,246 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+// batchSSHOptions are passed to ssh when it runs on several nodes at once,
+// where it cannot prompt for a password or to accept a new host key. Hosts
+// must already be in known_hosts unless --accept-new-host-keys is given; see
+// batchSSHArgs.
+var batchSSHOptions = []string{"-o", "BatchMode=yes"}
+
+// sshConnectionFailed is the exit status of ssh when it could not connect
+const sshConnectionFailed = 255
+
+// nodeTarget is a node of an instance that a command runs against
+type nodeTarget struct {
+	// Number counts from 1, as in 'hyperbolic instances <instance-id>'
+	Number   int
+	Endpoint hyperbolic.SSHEndpoint
+}
+
+// errStoppedByFailFast is the result of a node that was not run, or was
+// stopped, because another node failed first with --fail-fast
+var errStoppedByFailFast = errors.New("stopped after another node failed (--fail-fast)")
+
+// nodeResult is the outcome of running a command against one node
+type nodeResult struct {
+	Target nodeTarget
+	Err    error
+}
+
+// addNodeFlags adds --nodes and --max-parallel to a command that runs
+// against several nodes of an instance
+func addNodeFlags(cmd *cobra.Command) {
+	cmd.Flags().IntSlice("nodes", nil, "Nodes to run on, e.g. --nodes 1,3 (default all nodes)")
+	cmd.Flags().Int("max-parallel", 0, "Maximum number of nodes to run on at once (0 for all)")
+}
+
+// addHostKeyFlag adds --accept-new-host-keys to a command that runs ssh in
+// batch mode
+func addHostKeyFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("accept-new-host-keys", false, "Trust and record the host key of nodes not yet in known_hosts instead of failing (ssh StrictHostKeyChecking=accept-new)")
+}
+
+// batchSSHArgs returns batchSSHOptions, with new host keys accepted if
+// --accept-new-host-keys was given. Without it ssh fails on a node it has not
+// connected to before, as it cannot ask whether to trust the node's key.
+func batchSSHArgs(cmd *cobra.Command) []string {
+	options := append([]string{}, batchSSHOptions...)
+	if accept, _ := cmd.Flags().GetBool("accept-new-host-keys"); accept {
+		options = append(options, "-o", "StrictHostKeyChecking=accept-new")
+	}
+	return options
+}
+
+// selectNodeTargets returns the nodes of instance chosen with --nodes, or
+// all of them. Every chosen node must be reachable over SSH.
+func selectNodeTargets(cmd *cobra.Command, instance hyperbolic.Instance) ([]nodeTarget, error) {
+	numbers, _ := cmd.Flags().GetIntSlice("nodes")
+	if len(numbers) == 0 {
+		for i := range instance.Nodes {
+			numbers = append(numbers, i+1)
+		}
+	}
+
+	var targets []nodeTarget
+	seen := map[int]bool{}
+	for _, number := range numbers {
+		if number < 1 {
+			return nil, usageErrorf("invalid --nodes: node numbers start at 1")
+		}
+		if seen[number] {
+			continue
+		}
+		seen[number] = true
+
+		endpoint, err := instanceSSHEndpoint(instance, number)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, nodeTarget{Number: number, Endpoint: endpoint})
+	}
+	if len(targets) == 0 {
+		return nil, noSSHEndpointError(instance)
+	}
+	return targets, nil
+}
+
+// maxParallel returns the limit chosen with --max-parallel, where 0 means
+// no limit
+func maxParallel(cmd *cobra.Command) (int, error) {
+	limit, _ := cmd.Flags().GetInt("max-parallel")
+	if limit < 0 {
+		return 0, usageErrorf("--max-parallel cannot be negative")
+	}
+	return limit, nil
+}
+
+// runOnNodes calls run for every target, at most limit at a time (0 for no
+// limit), starting them in order. Lines written to the stdout and stderr
+// given to run are passed on to stdout and stderr with the node number in
+// front, so the output of nodes running at the same time is not interleaved
+// within a line. If failFast is set, the first failure cancels the nodes
+// still running and the rest are not started; their result is
+// errStoppedByFailFast. Results are in the order of targets.
+func runOnNodes(ctx context.Context, targets []nodeTarget, limit int, failFast bool, stdout, stderr io.Writer, run func(ctx context.Context, target nodeTarget, stdout, stderr io.Writer) error) []nodeResult {
+	if limit <= 0 || limit > len(targets) {
+		limit = len(targets)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var stopped atomic.Bool
+	results := make([]nodeResult, len(targets))
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		slots <- struct{}{}
+		if stopped.Load() {
+			<-slots
+			results[i] = nodeResult{Target: target, Err: errStoppedByFailFast}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			prefix := fmt.Sprintf("[node %d] ", target.Number)
+			out := &prefixWriter{w: stdout, prefix: prefix, mu: &mu}
+			errOut := &prefixWriter{w: stderr, prefix: prefix, mu: &mu}
+			err := run(ctx, target, out, errOut)
+			out.Flush()
+			errOut.Flush()
+
+			// Only the first failure counts; nodes failing after it were
+			// most likely cancelled by it
+			if err != nil && failFast {
+				if stopped.CompareAndSwap(false, true) {
+					cancel()
+				} else {
+					err = errStoppedByFailFast
+				}
+			}
+			results[i] = nodeResult{Target: target, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// nodeResultsError prints how each node fared, if there was more than one or
+// any failed, and returns an error if any failed
+func nodeResultsError(results []nodeResult, w io.Writer, what string) error {
+	failed, stopped := 0, 0
+	for _, result := range results {
+		switch {
+		case errors.Is(result.Err, errStoppedByFailFast):
+			stopped++
+		case result.Err != nil:
+			failed++
+		}
+	}
+	if len(results) > 1 || failed > 0 {
+		fmt.Fprintln(w)
+		for _, result := range results {
+			fmt.Fprintf(w, "node %d (%s): %s\n", result.Target.Number, result.Target.Endpoint.Destination(), describeNodeError(result.Err))
+		}
+	}
+	if failed > 0 && stopped > 0 {
+		return fmt.Errorf("%s failed on %d of %d node(s); --fail-fast stopped the other %d", what, failed, len(results), stopped)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s failed on %d of %d node(s)", what, failed, len(results))
+	}
+	return nil
+}
+
+// describeNodeError describes how ssh, or a program run over it, exited on
+// a node
+func describeNodeError(err error) string {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == sshConnectionFailed:
+		return fmt.Sprintf("exit status %d (ssh could not connect or the session failed)", sshConnectionFailed)
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+		return fmt.Sprintf("exit status %d", exitErr.ExitCode())
+	}
+	return err.Error()
+}
+
+// prefixWriter writes whole lines to w with prefix in front of each. Writers
+// sharing mu never write at the same time.
+type prefixWriter struct {
+	w       io.Writer
+	prefix  string
+	mu      *sync.Mutex
+	partial []byte
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.partial = append(p.partial, data...)
+	for {
+		i := bytes.IndexByte(p.partial, '\n')
+		if i < 0 {
+			return len(data), nil
+		}
+		if err := p.writeLine(p.partial[:i+1]); err != nil {
+			return len(data), err
+		}
+		p.partial = p.partial[i+1:]
+	}
+}
+
+// Flush writes out a final line that did not end in a newline
+func (p *prefixWriter) Flush() {
+	if len(p.partial) > 0 {
+		p.writeLine(append(p.partial, '\n'))
+		p.partial = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := fmt.Fprintf(p.w, "%s%s", p.prefix, line)
+	return err
+}
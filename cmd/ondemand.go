@@ -4,181 +4,135 @@ Copyright © 2025 Hyperbolic Labs
 package cmd
 
 import (
-	"encoding/json"
+	"cmp"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 
-	"github.com/olekukonko/tablewriter"
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
 	"github.com/spf13/cobra"
 )
 
-// VirtualMachineOption represents a VM option from the API
-type VirtualMachineOption struct {
-	GPUCount    int     `json:"gpuCount"`
-	CostPerHour float64 `json:"costPerHour"`
-}
-
-// VirtualMachineOptions represents the response from /v2/marketplace/virtual-machine-options
-type VirtualMachineOptions []VirtualMachineOption
-
-// BareMetalNetworkOption represents network configuration for bare metal instances
-type BareMetalNetworkOption struct {
-	GPUCount    int     `json:"gpuCount"`
-	CostPerHour float64 `json:"costPerHour"`
-}
-
-// BareMetalOptions represents the response from /v2/marketplace/bare-metal-options
-type BareMetalOptions struct {
-	Ethernet   BareMetalNetworkOption `json:"ethernet"`
-	Infiniband BareMetalNetworkOption `json:"infiniband"`
-}
-
-
+// onDemandGPUModel is the GPU offered for on-demand rentals. The options
+// endpoints do not report it.
+const onDemandGPUModel = "H100-SXM5-80GB"
 
 // ondemandCmd represents the ondemand command
 var ondemandCmd = &cobra.Command{
 	Use:   "ondemand",
 	Short: "View available on-demand GPU instances",
 	Long:  `View all available on-demand GPU instances with pricing information.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		jsonFormat, _ := cmd.Flags().GetBool("json")
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := selectedOutput(cmd)
+		if err != nil {
+			return err
+		}
 
-		if jsonFormat {
-			// If json flag is set, print raw JSON responses
-			err := printRawJSON()
-			if err != nil {
-				fmt.Printf("Error fetching data: %v\n", err)
-				return
-			}
-		} else {
-			// Otherwise, format as a table
-			err := printOnDemandTable()
-			if err != nil {
-				fmt.Printf("Error fetching data: %v\n", err)
-				return
-			}
+		builder, err := selectListColumns(cmd, onDemandTableBuilder)
+		if err != nil {
+			return err
 		}
-	},
-}
 
-func printRawJSON() error {
-	vmOptions, bareMetalOptions, err := fetchOnDemandOptions()
-	if err != nil {
-		return err
-	}
+		vmOptions, bareMetalOptions, err := fetchOnDemandOptions(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("error fetching data: %w", err)
+		}
 
-	response := map[string]interface{}{
-		"virtualMachineOptions": vmOptions,
-		"bareMetalOptions":      bareMetalOptions,
-	}
+		output, err := onDemandOffers(vmOptions, bareMetalOptions)
+		if err != nil {
+			return fmt.Errorf("error fetching data: %w", err)
+		}
+		if err := sortListItems(cmd, onDemandTableBuilder, output.Items); err != nil {
+			return err
+		}
+		table := builder.Table(output.Items)
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error marshaling JSON: %v", err)
-	}
+		if !format.IsTable() {
+			return printStructured(format, output, table)
+		}
 
-	fmt.Println(string(jsonData))
-	return nil
-}
+		table.Render(os.Stdout, format.Wide())
+		fmt.Println("\nBare Metal instances can be configured in multiples of 8 GPUs, subject to availability.")
+		fmt.Printf("\nInfiniBand adds $0.50 to the base price of $%.2f/hr\n", bareMetalOptions.Ethernet.CostPerHour)
 
-func printOnDemandTable() error {
-	vmOptions, bareMetalOptions, err := fetchOnDemandOptions()
-	if err != nil {
-		return err
-	}
+		fmt.Println("For rental options, run: `hyperbolic rent ondemand --help`")
+		return nil
+	},
+}
 
-	gpuType := "H100-SXM5-80GB"
+// onDemandTableBuilder describes the columns and sort orders of
+// 'hyperbolic ondemand'
+var onDemandTableBuilder = printer.TableBuilder[onDemandOfferOutput]{
+	Fields: []printer.Field[onDemandOfferOutput]{
+		{Name: "gpu-model", Header: "GPU Type", Value: func(offer onDemandOfferOutput) string {
+			return offer.GPUModel
+		}},
+		{Name: "type", Header: "Instance Type", Value: func(offer onDemandOfferOutput) string {
+			switch offer.NetworkType {
+			case "ethernet":
+				return "Bare Metal (Ethernet)"
+			case "infiniband":
+				return "Bare Metal (InfiniBand)"
+			}
+			return instanceTypeLabel(hyperbolic.InstanceType(offer.Type))
+		}},
+		{Name: "count", Header: "Count", Value: func(offer onDemandOfferOutput) string {
+			if offer.Type == string(hyperbolic.InstanceTypeBareMetal) && len(offer.GPUCounts) > 0 {
+				return fmt.Sprintf("%d–%d (×8)", offer.GPUCounts[0], offer.GPUCounts[len(offer.GPUCounts)-1])
+			}
+			// Virtual machines - show all available GPU counts
+			var counts []string
+			for _, count := range offer.GPUCounts {
+				counts = append(counts, strconv.Itoa(count))
+			}
+			return strings.Join(counts, ", ")
+		}},
+		{Name: "price", Header: "Price/GPU/hr", Value: func(offer onDemandOfferOutput) string {
+			return fmt.Sprintf("$%.2f", offer.PricePerGPUHourUSD)
+		}},
+	},
+	SortKeys: []printer.SortKey[onDemandOfferOutput]{
+		{Name: "price", Compare: func(a, b onDemandOfferOutput) int {
+			return cmp.Compare(a.PricePerGPUHourUSD, b.PricePerGPUHourUSD)
+		}},
+		{Name: "gpu-model", Compare: func(a, b onDemandOfferOutput) int {
+			return strings.Compare(a.GPUModel, b.GPUModel)
+		}},
+		// The most GPUs that can be rented at once
+		{Name: "available", Compare: func(a, b onDemandOfferOutput) int {
+			return cmp.Compare(slices.Max(append([]int{0}, a.GPUCounts...)), slices.Max(append([]int{0}, b.GPUCounts...)))
+		}},
+	},
+}
 
-	// Virtual-machine: min/max GPUs
+// onDemandOffers returns the offers shown by 'hyperbolic ondemand', which
+// are the same rows in every output format
+func onDemandOffers(vmOptions hyperbolic.VirtualMachineOptions, bareMetalOptions hyperbolic.BareMetalOptions) (onDemandOfferListOutput, error) {
 	if len(vmOptions) == 0 {
-		return fmt.Errorf("no virtual machine options available")
-	}
-	
-	// Sort VM options by GPU count for consistent display
-	sort.Slice(vmOptions, func(i, j int) bool {
-		return vmOptions[i].GPUCount < vmOptions[j].GPUCount
-	})
-	
-	vmBase := vmOptions[0].CostPerHour
-
-	// Bare-metal Ethernet: GPU count and price per GPU-hr
-	ethMin, ethMax := 8, bareMetalOptions.Ethernet.GPUCount
-	bmEthPrice := bareMetalOptions.Ethernet.CostPerHour
-
-	// Bare-metal InfiniBand: GPU count and price per GPU-hr
-	ibMin, ibMax := 8, bareMetalOptions.Infiniband.GPUCount
-	bmIBPrice := bareMetalOptions.Infiniband.CostPerHour
-
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Header(
-		"GPU Type",
-		"Instance Type",
-		"Count",
-		"Price/GPU/hr",
-	)
-
-	// VM row - show all available GPU counts
-	var vmCountStrs []string
-	for _, option := range vmOptions {
-		vmCountStrs = append(vmCountStrs, strconv.Itoa(option.GPUCount))
+		return onDemandOfferListOutput{}, fmt.Errorf("no virtual machine options available")
 	}
-	vmCountStr := strings.Join(vmCountStrs, ", ")
-	
-	table.Append([]string{
-		gpuType,
-		"Virtual Machine",
-		vmCountStr,
-		fmt.Sprintf("$%.2f", vmBase),
-	})
-
-	// BM Ethernet row
-	if ethMax > 0 {
-		table.Append([]string{
-			gpuType,
-			"Bare Metal (Ethernet)",
-			fmt.Sprintf("%d–%d (×8)", ethMin, ethMax),
-			fmt.Sprintf("$%.2f", bmEthPrice),
-		})
-	}
-
-	// BM InfiniBand row
-	if ibMax > 0 {
-		table.Append([]string{
-			gpuType,
-			"Bare Metal (InfiniBand)",
-			fmt.Sprintf("%d–%d (×8)", ibMin, ibMax),
-			fmt.Sprintf("$%.2f", bmIBPrice),
-		})
-	}
-
-	table.Render()
-	fmt.Println("\nBare Metal instances can be configured in multiples of 8 GPUs, subject to availability.")
-	fmt.Printf("\nInfiniBand adds $0.50 to the base price of $%.2f/hr\n", bmEthPrice)
-
-	fmt.Println("For rental options, run: `hyperbolic rent ondemand --help`")
-	return nil
+	return newOnDemandOfferListOutput(vmOptions, bareMetalOptions), nil
 }
 
-func fetchOnDemandOptions() (VirtualMachineOptions, BareMetalOptions, error) {
-	// Get API key
-	apiKey, err := GetAPIKey()
+func fetchOnDemandOptions(ctx context.Context) (hyperbolic.VirtualMachineOptions, hyperbolic.BareMetalOptions, error) {
+	client, err := newClient()
 	if err != nil {
-		return nil, BareMetalOptions{}, fmt.Errorf("failed to get API key: %v", err)
+		return nil, hyperbolic.BareMetalOptions{}, err
 	}
 
 	// Fetch both VM and bare metal options concurrently
-	vmChan := make(chan VirtualMachineOptions, 1)
-	bareMetalChan := make(chan BareMetalOptions, 1)
+	vmChan := make(chan hyperbolic.VirtualMachineOptions, 1)
+	bareMetalChan := make(chan hyperbolic.BareMetalOptions, 1)
 	errChan := make(chan error, 2)
 
 	// Fetch VM options
 	go func() {
-		vmOptions, err := fetchVirtualMachineOptions(apiKey)
+		vmOptions, err := client.GetVirtualMachineOptions(ctx)
 		if err != nil {
 			errChan <- err
 			return
@@ -188,7 +142,7 @@ func fetchOnDemandOptions() (VirtualMachineOptions, BareMetalOptions, error) {
 
 	// Fetch bare metal options
 	go func() {
-		bareMetalOptions, err := fetchBareMetalOptions(apiKey)
+		bareMetalOptions, err := client.GetBareMetalOptions(ctx)
 		if err != nil {
 			errChan <- err
 			return
@@ -197,8 +151,8 @@ func fetchOnDemandOptions() (VirtualMachineOptions, BareMetalOptions, error) {
 	}()
 
 	// Wait for results
-	var vmOptions VirtualMachineOptions
-	var bareMetalOptions BareMetalOptions
+	var vmOptions hyperbolic.VirtualMachineOptions
+	var bareMetalOptions hyperbolic.BareMetalOptions
 	receivedCount := 0
 
 	for receivedCount < 2 {
@@ -210,86 +164,15 @@ func fetchOnDemandOptions() (VirtualMachineOptions, BareMetalOptions, error) {
 			bareMetalOptions = bm
 			receivedCount++
 		case err := <-errChan:
-			return nil, BareMetalOptions{}, err
+			return nil, hyperbolic.BareMetalOptions{}, err
 		}
 	}
 
 	return vmOptions, bareMetalOptions, nil
 }
 
-func fetchVirtualMachineOptions(apiKey string) (VirtualMachineOptions, error) {
-	url := "https://api.hyperbolic.xyz/v2/marketplace/virtual-machine-options"
-	
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
-	}
-
-	var vmOptions VirtualMachineOptions
-	if err := json.Unmarshal(body, &vmOptions); err != nil {
-		return nil, fmt.Errorf("error parsing VM options response: %v", err)
-	}
-
-	return vmOptions, nil
-}
-
-func fetchBareMetalOptions(apiKey string) (BareMetalOptions, error) {
-	url := "https://api.hyperbolic.xyz/v2/marketplace/bare-metal-options"
-	
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return BareMetalOptions{}, fmt.Errorf("error creating request: %v", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return BareMetalOptions{}, fmt.Errorf("error sending request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return BareMetalOptions{}, fmt.Errorf("API request failed with status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return BareMetalOptions{}, fmt.Errorf("error reading response: %v", err)
-	}
-
-	var bareMetalOptions BareMetalOptions
-	if err := json.Unmarshal(body, &bareMetalOptions); err != nil {
-		return BareMetalOptions{}, fmt.Errorf("error parsing bare metal options response: %v", err)
-	}
-
-	return bareMetalOptions, nil
-}
-
-
-
 func init() {
 	rootCmd.AddCommand(ondemandCmd)
-	ondemandCmd.Flags().Bool("json", false, "Output raw JSON response")
-} 
\ No newline at end of file
+	ondemandCmd.Flags().Bool("json", false, "Output as JSON (same as -o json)")
+	addListFlags(ondemandCmd, onDemandTableBuilder)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/spf13/cobra"
+)
+
+// Set by the persistent -o/--output flag on the root command
+var outputFormat string
+
+// selectedOutput returns the output format chosen with -o/--output. The
+// older per-command --json flag is kept as a shorthand for -o json.
+func selectedOutput(cmd *cobra.Command) (printer.Format, error) {
+	if jsonFlag := cmd.Flags().Lookup("json"); jsonFlag != nil && jsonFlag.Changed {
+		if jsonFormat, _ := cmd.Flags().GetBool("json"); jsonFormat {
+			if outputFormat != "" && outputFormat != printer.FormatJSON {
+				return printer.Format{}, usageErrorf("--json cannot be combined with -o %s", outputFormat)
+			}
+			return printer.ParseFormat(printer.FormatJSON)
+		}
+	}
+
+	format, err := printer.ParseFormat(outputFormat)
+	if err != nil {
+		return printer.Format{}, &UsageError{Err: err}
+	}
+	return format, nil
+}
+
+// printStructured writes data, or table for csv and tsv, to stdout in a
+// non-table format
+func printStructured(format printer.Format, data interface{}, table *printer.Table) error {
+	return format.Print(os.Stdout, data, table)
+}
+
+// addListFlags adds --sort-by, --reverse and --columns to a command that
+// lists items with builder
+func addListFlags[T any](cmd *cobra.Command, builder printer.TableBuilder[T]) {
+	cmd.Flags().String("sort-by", "", "Sort by: "+strings.Join(builder.SortKeyNames(), "|"))
+	cmd.Flags().Bool("reverse", false, "Reverse the sort order")
+	cmd.Flags().StringSlice("columns", nil, "Columns to show, in order: "+strings.Join(builder.FieldNames(), ","))
+}
+
+// listSortKeys are the --sort-by keys of the listing commands. Each command
+// supports the ones that apply to what it lists.
+var listSortKeys = []string{"price", "gpu-model", "available", "region", "ram", "uptime"}
+
+// sortListItems sorts items as chosen with --sort-by and --reverse
+func sortListItems[T any](cmd *cobra.Command, builder printer.TableBuilder[T], items []T) error {
+	sortBy, _ := cmd.Flags().GetString("sort-by")
+	reverse, _ := cmd.Flags().GetBool("reverse")
+	if err := builder.Sort(items, sortBy, reverse); err != nil {
+		if slices.Contains(listSortKeys, sortBy) {
+			return usageErrorf("invalid --sort-by: '%s' does not report %s. Must be one of: %s", cmd.CommandPath(), sortBy, strings.Join(builder.SortKeyNames(), ", "))
+		}
+		return &UsageError{Err: fmt.Errorf("invalid --sort-by: %w", err)}
+	}
+	return nil
+}
+
+// selectListColumns returns builder with only the columns chosen with
+// --columns, or builder itself if the flag was not given
+func selectListColumns[T any](cmd *cobra.Command, builder printer.TableBuilder[T]) (printer.TableBuilder[T], error) {
+	if !cmd.Flags().Changed("columns") {
+		return builder, nil
+	}
+	columns, _ := cmd.Flags().GetStringSlice("columns")
+	selected, err := builder.Select(columns)
+	if err != nil {
+		return builder, &UsageError{Err: fmt.Errorf("invalid --columns: %w", err)}
+	}
+	return selected, nil
+}
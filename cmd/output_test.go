@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestListSortKeys(t *testing.T) {
+	tests := []struct {
+		command string
+		keys    []string
+	}{
+		{"spot", marketplaceTableBuilder.SortKeyNames()},
+		{"instances", instancesTableBuilder.SortKeyNames()},
+		{"ondemand", onDemandTableBuilder.SortKeyNames()},
+	}
+	want := map[string][]string{
+		"spot":      {"price", "gpu-model", "available", "region", "ram"},
+		"instances": {"price", "gpu-model", "ram", "uptime"},
+		"ondemand":  {"price", "gpu-model", "available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if !slices.Equal(tt.keys, want[tt.command]) {
+				t.Errorf("sort keys are %v, want %v", tt.keys, want[tt.command])
+			}
+			// Keys keep the order of listSortKeys, so help reads the same everywhere
+			last := -1
+			for _, key := range tt.keys {
+				i := slices.Index(listSortKeys, key)
+				if i <= last {
+					t.Errorf("sort key %q is not in listSortKeys or is out of order", key)
+				}
+				last = i
+			}
+
+			cmd, _, err := rootCmd.Find([]string{tt.command})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if usage := cmd.Flags().Lookup("sort-by").Usage; !strings.Contains(usage, strings.Join(tt.keys, "|")) {
+				t.Errorf("--sort-by help %q does not list %v", usage, tt.keys)
+			}
+		})
+	}
+}
+
+func TestSortListItemsUnsupportedKey(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"spot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resetFlags(rootCmd)
+	t.Cleanup(func() { resetFlags(rootCmd) })
+	if err := cmd.Flags().Set("sort-by", "uptime"); err != nil {
+		t.Fatal(err)
+	}
+
+	err = sortListItems(cmd, marketplaceTableBuilder, nil)
+	if exitCode(err) != ExitUsage {
+		t.Fatalf("expected a usage error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "does not report uptime") || !strings.Contains(err.Error(), "price, gpu-model, available, region, ram") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
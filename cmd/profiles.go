@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/credentials"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage CLI configuration.",
+	Long:  `Manage the Hyperbolic CLI configuration stored in ~/.hyperbolic/config.json.`,
+}
+
+// profilesCmd represents the config profiles command
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Manage named profiles for multiple API keys.",
+	Long: `Manage named profiles, each holding the API key (and optionally API URL) for one Hyperbolic account.
+
+Add a profile with 'hyperbolic auth --profile NAME YOUR_API_KEY'. Select a profile for a single command with --profile NAME or the HYPERBOLIC_PROFILE environment variable, or make it the default with 'hyperbolic config profiles use NAME'.`,
+}
+
+var profilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured profiles.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(config.Profiles))
+		for name := range config.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if len(names) == 0 {
+			fmt.Println("No profiles found. Run 'hyperbolic auth --profile NAME YOUR_API_KEY' to add one.")
+			return nil
+		}
+
+		active := config.ActiveProfileName()
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("ACTIVE", "PROFILE", "STORE", "API KEY", "API URL")
+		for _, name := range names {
+			profile := config.Profiles[name]
+			marker := ""
+			if name == active {
+				marker = "*"
+			}
+			// Keys outside the config file are not read, to avoid prompts
+			store, key := profile.CredentialStore, "-"
+			if store == "" {
+				store, key = credentials.StoreFile, maskAPIKey(profile.APIKey)
+			}
+			table.Append(marker, name, store, key, profile.APIURL)
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var profilesUseCmd = &cobra.Command{
+	Use:   "use <profile>",
+	Short: "Set the profile used by default.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		config, err := LoadConfig()
+		if err != nil {
+			return err
+		}
+		if _, exists := config.Profiles[name]; !exists {
+			return &NotFoundError{Kind: "profile", ID: name}
+		}
+
+		config.CurrentProfile = name
+		if err := SaveConfig(config); err != nil {
+			return fmt.Errorf("error saving configuration: %w", err)
+		}
+
+		fmt.Printf("✓ Now using profile '%s'\n", name)
+		return nil
+	},
+}
+
+var profilesDeleteCmd = &cobra.Command{
+	Use:   "delete <profile>",
+	Short: "Delete a profile and its stored API key.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		config, err := LoadConfig()
+		if err != nil {
+			return err
+		}
+		if _, exists := config.Profiles[name]; !exists {
+			return &NotFoundError{Kind: "profile", ID: name}
+		}
+
+		if err := deleteProfileAPIKey(config, name); err != nil {
+			return fmt.Errorf("error deleting API key for profile '%s': %w", name, err)
+		}
+		delete(config.Profiles, name)
+		if config.CurrentProfile == name {
+			config.CurrentProfile = ""
+		}
+		if err := SaveConfig(config); err != nil {
+			return fmt.Errorf("error saving configuration: %w", err)
+		}
+
+		fmt.Printf("✓ Deleted profile '%s'\n", name)
+		return nil
+	},
+}
+
+// maskAPIKey hides all but the first and last four characters of an API key
+func maskAPIKey(apiKey string) string {
+	if apiKey == "" {
+		return ""
+	}
+	if len(apiKey) <= 8 {
+		return "********"
+	}
+	return apiKey[:4] + "..." + apiKey[len(apiKey)-4:]
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(profilesCmd)
+	profilesCmd.AddCommand(profilesListCmd)
+	profilesCmd.AddCommand(profilesUseCmd)
+	profilesCmd.AddCommand(profilesDeleteCmd)
+}
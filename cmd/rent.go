@@ -4,59 +4,17 @@ Copyright © 2025 Hyperbolic Labs
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
 	"github.com/spf13/cobra"
 )
 
-type RentRequest struct {
-	ClusterName string `json:"cluster_name"`
-	NodeName    string `json:"node_name"`
-	GpuCount    int    `json:"gpu_count"`
-	Image       *Image `json:"image,omitempty"`
-}
-
-type Image struct {
-	Name  string `json:"name"`
-	Ports []int  `json:"ports,omitempty"`
-}
-
-// OnDemand request structures
-type VirtualMachineRentalRequest struct {
-	ConfigID string `json:"configId"`
-	GPUCount string `json:"gpuCount"`
-}
-
-type BareMetalRentalRequest struct {
-	ConfigID    string `json:"configId"`
-	NetworkType string `json:"networkType"`
-	GPUCount    int    `json:"gpuCount"`
-}
-
-// Response structures
-type SpotRentResponse struct {
-	InstanceID string `json:"instance_id"`
-	Status     string `json:"status"`
-	Message    string `json:"message"`
-}
-
-type OnDemandRentResponse struct {
-	ID           int `json:"id"`
-	ExternalID   string `json:"externalId"`
-	CostPerHour  int `json:"costPerHour"`
-	Meta         struct {
-		Name        string `json:"name"`
-		GPUCount    int    `json:"gpu_count"`
-		RentalType  string `json:"rental_type"`
-		NetworkType string `json:"network_type"`
-	} `json:"meta"`
-}
-
 // rentCmd represents the rent command
 var rentCmd = &cobra.Command{
 	Use:   "rent",
@@ -72,9 +30,10 @@ For Marketplace-specific options, run:
 'hyperbolic rent ondemand --help'
 
 To view available instances, run 'hyperbolic spot' or 'hyperbolic ondemand'.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Default to spot if no subcommand is provided
-		rentSpotInstance(cmd)
+		return rentSpotInstance(cmd)
 	},
 }
 
@@ -84,20 +43,45 @@ var rentSpotCmd = &cobra.Command{
 	Short: "Rent a GPU instance from the spot marketplace",
 	Long: `Rent containerized H100s from $0.99/hr, A100s, 4090s, etc. subject to availability.
 
-REQUIRED FLAGS:
+REQUIRED FLAGS (unless --auto is given):
   --cluster-name    Cluster name for the instance
   --node-name       Node name for the instance
 
+AUTOMATIC NODE SELECTION:
+  --auto            Rent on the cheapest node with enough free GPUs that meets these requirements:
+  --gpu-model       GPU models containing this text, e.g. 'h100' or '4090'
+  --max-price       Maximum price per GPU per hour, in USD
+  --region          Region, e.g. 'us-east'
+  --cluster-name    Cluster, if given
+  If another rental takes a node's GPUs first, the next cheapest node is tried.
+
+WAITING FOR CAPACITY:
+  --until-available Keep checking the marketplace until a node meeting the --auto requirements has free GPUs, then rent it
+  --poll-interval   How often to check (default: 10s)
+  --deadline        When to give up, as a duration (2h) or an RFC 3339 time (default: never)
+  --notify-command  Shell command to run once rented, with HYPERBOLIC_INSTANCE_ID, HYPERBOLIC_CLUSTER_NAME,
+                    HYPERBOLIC_NODE_NAME and HYPERBOLIC_GPU_COUNT set
+  Only one instance is ever rented: if a rental attempt fails without a clear answer, your instances
+  are checked before another attempt is made. A new rental of the same GPU count on the node tried
+  counts as made, even if it was rented by other means in the meantime.
+
 OPTIONAL FLAGS:
   --gpu-count       Number of GPUs to rent (default: 1)
   --ports           Ports to expose (up to 2 ports) 
+  --wait            Wait until the instance is running and reachable over SSH
+  --wait-timeout    How long --wait waits before giving up (default: 15m)
 
-EXAMPLE:
+EXAMPLES:
   hyperbolic rent spot --cluster-name cluster-1 --node-name node-1 --gpu-count 2 --ports 8080,3000
 
+  hyperbolic rent spot --auto --gpu-model 4090 --gpu-count 2 --max-price 0.5
+
+  hyperbolic rent spot --until-available --gpu-model h100 --gpu-count 8 --max-price 2 --deadline 6h --notify-command 'notify-send "Rented $HYPERBOLIC_INSTANCE_ID"'
+
 Use 'hyperbolic spot' to view available clusters and nodes.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		rentSpotInstance(cmd)
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rentSpotInstance(cmd)
 	},
 }
 
@@ -114,18 +98,23 @@ REQUIRED FLAGS:
 CONDITIONAL FLAGS:
   --network-type    Network type for bare-metal: 'ethernet' or 'infiniband' (required for bare-metal)
 
+OPTIONAL FLAGS:
+  --wait            Wait until the instance is running and reachable over SSH
+  --wait-timeout    How long --wait waits before giving up (default: 15m)
+
 EXAMPLES:
   hyperbolic rent ondemand --instance-type virtual-machine --gpu-count 4
 
   hyperbolic rent ondemand --instance-type bare-metal --network-type infiniband --gpu-count 16
 
 Use 'hyperbolic ondemand' to view available configurations and pricing.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		rentOnDemandInstance(cmd)
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rentOnDemandInstance(cmd)
 	},
 }
 
-func rentSpotInstance(cmd *cobra.Command) {
+func rentSpotInstance(cmd *cobra.Command) error {
 	clusterName, _ := cmd.Flags().GetString("cluster-name")
 	nodeName, _ := cmd.Flags().GetString("node-name")
 	gpuCount, _ := cmd.Flags().GetInt("gpu-count")
@@ -134,94 +123,103 @@ func rentSpotInstance(cmd *cobra.Command) {
 	// Validate and process ports
 	var ports []int
 	if len(portStrings) > 2 {
-		fmt.Printf("Error: Maximum of 2 ports can be specified, but %d were provided\n", len(portStrings))
-		return
+		return usageErrorf("maximum of 2 ports can be specified, but %d were provided", len(portStrings))
 	}
 
 	for _, portStr := range portStrings {
 		port, err := strconv.Atoi(portStr)
 		if err != nil {
-			fmt.Printf("Error: Invalid port number '%s': %v\n", portStr, err)
-			return
+			return usageErrorf("invalid port number '%s': %v", portStr, err)
 		}
 		if port < 1 || port > 65535 {
-			fmt.Printf("Error: Port number '%d' is out of valid range (1-65535)\n", port)
-			return
+			return usageErrorf("port number '%d' is out of valid range (1-65535)", port)
 		}
 		ports = append(ports, port)
 	}
 
-	// Get API key from config file
-	apiKey, err := GetAPIKey()
+	until, err := untilAvailableFromFlags(cmd)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		fmt.Printf("Please run 'hyperbolic auth YOUR_API_KEY' to save your API key\n")
-		fmt.Printf("(Get your API key from https://app.hyperbolic.ai/settings)\n")
-		return
+		return err
 	}
-
-	request := RentRequest{
-		ClusterName: clusterName,
-		NodeName:    nodeName,
-		GpuCount:    gpuCount,
+	auto, filter, err := spotAutoFromFlags(cmd)
+	if err != nil {
+		return err
 	}
-
-	// Only include image if ports are specified
-	if len(ports) > 0 {
-		request.Image = &Image{
-			Name:  "ghcr.io/hyperboliclabs/hyper-dos/sshbox",
-			Ports: ports,
+	if !auto {
+		var missing []string
+		for _, name := range []string{"cluster-name", "node-name"} {
+			if !cmd.Flags().Changed(name) {
+				missing = append(missing, `"`+name+`"`)
+			}
+		}
+		if len(missing) > 0 {
+			return usageErrorf("required flag(s) %s not set; give both, or use --auto to pick a node", strings.Join(missing, ", "))
 		}
 	}
 
-	requestBody, err := json.Marshal(request)
+	wait, err := waitOptionsFromFlags(cmd)
 	if err != nil {
-		fmt.Printf("Error creating request: %v\n", err)
-		return
+		return err
 	}
 
-	req, err := http.NewRequest("POST", "https://api.hyperbolic.xyz/v1/marketplace/instances/create", bytes.NewBuffer(requestBody))
+	client, err := newClient()
 	if err != nil {
-		fmt.Printf("Error creating HTTP request: %v\n", err)
-		return
+		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Printf("Error sending request: %v\n", err)
-		return
+	request := hyperbolic.RentRequest{
+		ClusterName: clusterName,
+		NodeName:    nodeName,
+		GpuCount:    gpuCount,
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("Error reading response: %v\n", err)
-		return
+	// Only include image if ports are specified
+	if len(ports) > 0 {
+		request.Image = &hyperbolic.Image{
+			Name:  hyperbolic.SpotImage,
+			Ports: ports,
+		}
 	}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		if resp.StatusCode == http.StatusInternalServerError {
-			fmt.Printf("The server is temporarily experiencing issues. Please try again in a few moments.\n")
+	var spotResponse hyperbolic.SpotRentResponse
+	if auto {
+		if until.enabled {
+			spotResponse, request, err = rentWhenAvailable(cmd.Context(), client, filter, request, until)
 		} else {
-			fmt.Printf("Error response from API (status code %d): %s\n", resp.StatusCode, string(body))
+			spotResponse, request, err = rentCheapestSpotNode(cmd.Context(), client, filter, request)
+		}
+		if err != nil {
+			return err
+		}
+		clusterName, nodeName = request.ClusterName, request.NodeName
+	} else {
+		spotResponse, err = client.RentSpotInstance(cmd.Context(), request)
+		if err != nil {
+			return rentError(err)
 		}
-		return
 	}
 
-	var spotResponse SpotRentResponse
-	if err := json.Unmarshal(body, &spotResponse); err != nil {
-		// Fallback to simple success message if parsing fails
+	if spotResponse.InstanceID == "" {
+		// Fallback to simple success message if the response had no ID
 		fmt.Printf("Successfully requested GPU instance.\n")
 		fmt.Printf("Configuration: %s/%s with %d GPU(s)\n", clusterName, nodeName, gpuCount)
 	} else {
 		fmt.Printf("Successfully requested GPU instance: %s\n", spotResponse.InstanceID)
 		fmt.Printf("Configuration: %s/%s with %d GPU(s)\n", clusterName, nodeName, gpuCount)
 	}
-	
+
+	if until.notifyCommand != "" {
+		runNotifyCommand(until.notifyCommand, spotResponse.InstanceID, request)
+	}
+
+	if wait.enabled {
+		if spotResponse.InstanceID != "" {
+			return waitForRental(cmd.Context(), client, spotResponse.InstanceID, wait)
+		}
+		fmt.Println()
+		fmt.Println("Cannot wait for the instance: the response did not include its ID.")
+	}
+
 	fmt.Println()
 	fmt.Println("To view the status and get the SSH command, run:")
 	fmt.Println("  hyperbolic instances")
@@ -230,97 +228,49 @@ func rentSpotInstance(cmd *cobra.Command) {
 		fmt.Println("To view public URLs for exposed ports, run:")
 		fmt.Println("  hyperbolic instances <instance-id>")
 	}
+	return nil
 }
 
-func rentOnDemandInstance(cmd *cobra.Command) {
+func rentOnDemandInstance(cmd *cobra.Command) error {
 	instanceType, _ := cmd.Flags().GetString("instance-type")
 	gpuCount, _ := cmd.Flags().GetInt("gpu-count")
 	networkType, _ := cmd.Flags().GetString("network-type")
 
 	// Validate instance type
 	if instanceType != "virtual-machine" && instanceType != "bare-metal" {
-		fmt.Printf("Error: Invalid instance type '%s'. Must be 'virtual-machine' or 'bare-metal'\n", instanceType)
-		return
+		return usageErrorf("invalid instance type '%s'. Must be 'virtual-machine' or 'bare-metal'", instanceType)
 	}
 
 	// Validate network type for bare metal
 	if instanceType == "bare-metal" {
 		if networkType != "ethernet" && networkType != "infiniband" {
-			fmt.Printf("Error: Invalid network type '%s' for bare-metal. Must be 'ethernet' or 'infiniband'\n", networkType)
-			return
-		}
-	}
-
-	// Get API key from config file
-	apiKey, err := GetAPIKey()
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		fmt.Printf("Please run 'hyperbolic auth YOUR_API_KEY' to save your API key\n")
-		fmt.Printf("(Get your API key from https://app.hyperbolic.ai/settings)\n")
-		return
-	}
-
-	var endpoint string
-	var requestBody []byte
-
-	if instanceType == "virtual-machine" {
-		endpoint = "https://api.hyperbolic.xyz/v2/marketplace/virtual-machine-rentals"
-		request := VirtualMachineRentalRequest{
-			ConfigID: "c6fd6253-cbb6-4ea8-a20c-47644b431f1c",
-			GPUCount: strconv.Itoa(gpuCount),
-		}
-		requestBody, err = json.Marshal(request)
-	} else { // bare-metal
-		endpoint = "https://api.hyperbolic.xyz/v2/marketplace/bare-metal-rentals"
-		request := BareMetalRentalRequest{
-			ConfigID:    "a3111bd4-550a-47d0-838a-0a52bff2ae3f",
-			NetworkType: networkType,
-			GPUCount:    gpuCount,
+			return usageErrorf("invalid network type '%s' for bare-metal. Must be 'ethernet' or 'infiniband'", networkType)
 		}
-		requestBody, err = json.Marshal(request)
 	}
 
+	wait, err := waitOptionsFromFlags(cmd)
 	if err != nil {
-		fmt.Printf("Error creating request: %v\n", err)
-		return
+		return err
 	}
 
-	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(requestBody))
+	client, err := newClient()
 	if err != nil {
-		fmt.Printf("Error creating HTTP request: %v\n", err)
-		return
+		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Printf("Error sending request: %v\n", err)
-		return
+	var onDemandResponse hyperbolic.OnDemandRentResponse
+	if instanceType == "virtual-machine" {
+		onDemandResponse, err = client.RentVirtualMachine(cmd.Context(), gpuCount)
+	} else { // bare-metal
+		onDemandResponse, err = client.RentBareMetal(cmd.Context(), gpuCount, networkType)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("Error reading response: %v\n", err)
-		return
+		return rentError(err)
 	}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		if resp.StatusCode == http.StatusInternalServerError {
-			fmt.Printf("The server is temporarily experiencing issues. Please try again in a few moments.\n")
-			fmt.Printf("If the problem persists, please contact support.\n")
-		} else {
-			fmt.Printf("Error response from API (status code %d): %s\n", resp.StatusCode, string(body))
-		}
-		return
-	}
-
-	var onDemandResponse OnDemandRentResponse
-	if err := json.Unmarshal(body, &onDemandResponse); err != nil {
-		// Fallback to simple success message if parsing fails
+	if onDemandResponse.ID == 0 {
+		// Fallback to simple success message if the response had no ID
 		fmt.Printf("Successfully requested on-demand GPU instance.\n")
 		if instanceType == "bare-metal" {
 			fmt.Printf("Configuration: %s with %d GPU(s), %s network\n", instanceType, gpuCount, networkType)
@@ -336,20 +286,131 @@ func rentOnDemandInstance(cmd *cobra.Command) {
 		}
 		fmt.Printf("Total cost: $%.2f/hour\n", float64(onDemandResponse.CostPerHour)/100)
 	}
-	
+
+	if wait.enabled {
+		if onDemandResponse.ID != 0 {
+			return waitForRental(cmd.Context(), client, strconv.Itoa(onDemandResponse.ID), wait)
+		}
+		fmt.Println()
+		fmt.Println("Cannot wait for the instance: the response did not include its ID.")
+	}
+
 	fmt.Println()
 	fmt.Println("To view the status and get the SSH command, run:")
 	fmt.Println("  hyperbolic instances")
+	return nil
+}
+
+// spotAutoFromFlags reads --auto and the requirements for the node it picks.
+// The requirements can only be given with --auto, which decides the node
+// itself, or --until-available, which implies it.
+func spotAutoFromFlags(cmd *cobra.Command) (bool, hyperbolic.MarketplaceFilter, error) {
+	auto, _ := cmd.Flags().GetBool("auto")
+	untilAvailable, _ := cmd.Flags().GetBool("until-available")
+	auto = auto || untilAvailable
+
+	var filter hyperbolic.MarketplaceFilter
+	filter.GPUModel, _ = cmd.Flags().GetString("gpu-model")
+	filter.Region, _ = cmd.Flags().GetString("region")
+	filter.Cluster, _ = cmd.Flags().GetString("cluster-name")
+	filter.MinGPUs, _ = cmd.Flags().GetInt("gpu-count")
+	maxPrice, _ := cmd.Flags().GetFloat64("max-price")
+
+	if !auto {
+		for _, name := range []string{"gpu-model", "max-price", "region"} {
+			if cmd.Flags().Changed(name) {
+				return false, filter, usageErrorf("--%s can only be used with --auto or --until-available", name)
+			}
+		}
+		return false, filter, nil
+	}
+	if cmd.Flags().Changed("node-name") {
+		return true, filter, usageErrorf("--node-name cannot be combined with --auto or --until-available, which pick the node")
+	}
+	if filter.MinGPUs < 1 {
+		return true, filter, usageErrorf("--gpu-count must be at least 1")
+	}
+	if maxPrice < 0 {
+		return true, filter, usageErrorf("--max-price cannot be negative")
+	}
+
+	var ok bool
+	if filter.MaxPrice, ok = maxPriceCents(maxPrice); !ok {
+		return true, filter, usageErrorf("--max-price %g is less than a cent; it is in USD per GPU per hour, e.g. 0.5", maxPrice)
+	}
+	return true, filter, nil
+}
+
+// rentCheapestSpotNode rents on the cheapest marketplace node that matches
+// filter and has enough free GPUs. Nodes whose GPUs are taken between listing
+// and renting reject the request, in which case the next cheapest node is
+// tried. The request is returned with the node that was rented on.
+func rentCheapestSpotNode(ctx context.Context, client *hyperbolic.Client, filter hyperbolic.MarketplaceFilter, request hyperbolic.RentRequest) (hyperbolic.SpotRentResponse, hyperbolic.RentRequest, error) {
+	marketplaceData, err := client.ListMarketplace(ctx, nil)
+	if err != nil {
+		return hyperbolic.SpotRentResponse{}, request, fmt.Errorf("error calling Hyperbolic API: %w", err)
+	}
+
+	candidates := filterMarketplaceInstances(marketplaceData.Instances, filter, false)
+	if len(candidates) == 0 {
+		return hyperbolic.SpotRentResponse{}, request, fmt.Errorf("no spot node has %d GPU(s) available that meet the requirements.\nRun 'hyperbolic spot --all' to see every node, or use --until-available to wait for one", request.GpuCount)
+	}
+	return rentOnSpotNodes(ctx, client, candidates, request)
+}
+
+// rentOnSpotNodes tries to rent on each candidate node in turn, moving on to
+// the next when a node rejects the rental for lack of capacity. Other errors
+// stop it at once, since the rental may have been made.
+func rentOnSpotNodes(ctx context.Context, client *hyperbolic.Client, candidates []hyperbolic.MarketplaceInstance, request hyperbolic.RentRequest) (hyperbolic.SpotRentResponse, hyperbolic.RentRequest, error) {
+	var lastErr error
+	for _, node := range candidates {
+		request.ClusterName = node.ClusterName
+		request.NodeName = node.ID
+		fmt.Printf("Renting on %s/%s (%s): %s at $%.2f per GPU per hour\n", node.ClusterName, node.ID, node.Location.Region, displayGPUModel(node.GPU().Model), float64(node.Pricing.Price.Amount)/100)
+
+		response, err := client.RentSpotInstance(ctx, request)
+		if err == nil {
+			return response, request, nil
+		}
+		var apiErr *hyperbolic.APIError
+		if !errors.As(err, &apiErr) || !apiErr.IsCapacityUnavailable() {
+			return response, request, rentError(err)
+		}
+		fmt.Printf("Node %s rejected the rental (%s); trying the next node\n", node.ID, strings.TrimSpace(apiErr.Body))
+		lastErr = err
+	}
+	return hyperbolic.SpotRentResponse{}, request, fmt.Errorf("every matching node rejected the rental; the last error was: %w", lastErr)
+}
+
+// addSpotAutoFlags adds --auto and the requirements for the node it picks
+func addSpotAutoFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("auto", false, "Rent on the cheapest node meeting the requirements instead of --node-name")
+	cmd.Flags().String("gpu-model", "", "With --auto, only use GPU models containing this text, e.g. 'h100' or '4090'")
+	cmd.Flags().Float64("max-price", 0, "With --auto, only use nodes costing at most this much per GPU per hour, in USD")
+	cmd.Flags().String("region", "", "With --auto, only use nodes in this region, e.g. 'us-east'")
+}
+
+// rentError adds context to a failed create call while keeping the
+// underlying error for exit code classification
+func rentError(err error) error {
+	var apiErr *hyperbolic.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusInternalServerError {
+		return fmt.Errorf("the server is temporarily experiencing issues. Please try again in a few moments.\nIf the problem persists, please contact support. (%w)", err)
+	}
+	if errors.As(err, &apiErr) && apiErr.IsInsufficientBalance() {
+		return fmt.Errorf("insufficient balance to rent this instance. Add funds at https://app.hyperbolic.ai/billing (%w)", err)
+	}
+	return fmt.Errorf("rental request failed: %w", err)
 }
 
 func init() {
 	rootCmd.AddCommand(rentCmd)
-	
+
 	// Set custom help template to hide usage, flags, and commands
 	rentCmd.SetHelpTemplate(`{{.Long}}
 
 `)
-	
+
 	// Add subcommands
 	rentCmd.AddCommand(rentSpotCmd)
 	rentCmd.AddCommand(rentOnDemandCmd)
@@ -359,28 +420,45 @@ func init() {
 	rentCmd.Flags().String("node-name", "", "Node name for the instance (required)")
 	rentCmd.Flags().Int("gpu-count", 1, "Number of GPUs to rent")
 	rentCmd.Flags().StringSlice("ports", []string{}, "Ports to expose (up to 2 ports, e.g., --ports 8080,3000 or --ports 8080 --ports 3000)")
-	
+
 	// Hide these flags from the main help
 	rentCmd.Flags().MarkHidden("cluster-name")
 	rentCmd.Flags().MarkHidden("node-name")
 	rentCmd.Flags().MarkHidden("gpu-count")
 	rentCmd.Flags().MarkHidden("ports")
+	addWaitFlags(rentCmd)
+	rentCmd.Flags().MarkHidden("wait")
+	rentCmd.Flags().MarkHidden("wait-timeout")
+	rentCmd.Flags().MarkHidden("wait-ssh")
+	rentCmd.Flags().MarkHidden("terminate-on-timeout")
+	addSpotAutoFlags(rentCmd)
+	rentCmd.Flags().MarkHidden("auto")
+	rentCmd.Flags().MarkHidden("gpu-model")
+	rentCmd.Flags().MarkHidden("max-price")
+	rentCmd.Flags().MarkHidden("region")
+	addUntilAvailableFlags(rentCmd)
+	rentCmd.Flags().MarkHidden("until-available")
+	rentCmd.Flags().MarkHidden("poll-interval")
+	rentCmd.Flags().MarkHidden("deadline")
+	rentCmd.Flags().MarkHidden("notify-command")
 
 	// Spot marketplace flags
 	rentSpotCmd.Flags().String("cluster-name", "", "Cluster name for the instance (required)")
 	rentSpotCmd.Flags().String("node-name", "", "Node name for the instance (required)")
 	rentSpotCmd.Flags().Int("gpu-count", 1, "Number of GPUs to rent")
 	rentSpotCmd.Flags().StringSlice("ports", []string{}, "Ports to expose (up to 2 ports, e.g., --ports 8080,3000 or --ports 8080 --ports 3000)")
-	
-	// Mark required flags for spot
-	rentSpotCmd.MarkFlagRequired("cluster-name")
-	rentSpotCmd.MarkFlagRequired("node-name")
-	
+
+	// --cluster-name and --node-name are required unless --auto is given
+	addSpotAutoFlags(rentSpotCmd)
+	addUntilAvailableFlags(rentSpotCmd)
+	addWaitFlags(rentSpotCmd)
+
 	// OnDemand marketplace flags
 	rentOnDemandCmd.Flags().String("instance-type", "", "Instance type: 'virtual-machine' or 'bare-metal' (required)")
 	rentOnDemandCmd.Flags().String("network-type", "", "Network type for bare-metal instances: 'ethernet' or 'infiniband' (required for bare-metal)")
 	rentOnDemandCmd.Flags().Int("gpu-count", 1, "Number of GPUs to rent")
-	
+
 	// Mark required flags for ondemand
 	rentOnDemandCmd.MarkFlagRequired("instance-type")
+	addWaitFlags(rentOnDemandCmd)
 }
@@ -0,0 +1,285 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"gopkg.in/yaml.v3"
+)
+
+// rentalSpecFile is the file read by 'hyperbolic plan' and 'hyperbolic
+// apply', describing the rentals that should exist
+type rentalSpecFile struct {
+	// Name identifies the rentals managed for this file. It defaults to the
+	// file name without its extension.
+	Name    string       `yaml:"name"`
+	Rentals []rentalSpec `yaml:"rentals"`
+}
+
+// rentalSpec is one entry of a spec file: Count rentals of the same kind
+type rentalSpec struct {
+	Name string `yaml:"name"`
+	// Type is an InstanceType: spot, virtual-machine or bare-metal
+	Type string `yaml:"type"`
+	// Count defaults to 1. Zero asks for no rentals, which with --prune
+	// terminates the existing ones.
+	Count *int `yaml:"count"`
+	// GPUCount is per rental, across all of its nodes. It defaults to 1 for
+	// spot and virtual machine rentals.
+	GPUCount int `yaml:"gpuCount"`
+
+	// Spot only: where to rent
+	GPUModel string  `yaml:"gpuModel"`
+	Region   string  `yaml:"region"`
+	Cluster  string  `yaml:"cluster"`
+	MaxPrice float64 `yaml:"maxPrice"` // per GPU per hour, in USD
+	Ports    []int   `yaml:"ports"`
+
+	// Bare metal only: "ethernet" or "infiniband"
+	NetworkType string `yaml:"networkType"`
+}
+
+// count returns the number of rentals wanted
+func (r rentalSpec) count() int {
+	if r.Count == nil {
+		return 1
+	}
+	return *r.Count
+}
+
+// matches reports whether an existing instance satisfies the spec. Details
+// the API does not report for an instance are not compared, and neither are
+// the region or price of spot rentals, which only guide where to rent.
+func (r rentalSpec) matches(instance hyperbolic.Instance) bool {
+	if string(instance.Type) != r.Type {
+		return false
+	}
+	if instance.GPUCount != 0 && instance.GPUCount != r.GPUCount {
+		return false
+	}
+	if r.GPUModel != "" && instance.GPUModel != "" && !strings.Contains(strings.ToLower(instance.GPUModel), strings.ToLower(r.GPUModel)) {
+		return false
+	}
+	if r.NetworkType != "" && instance.NetworkType != "" && !strings.EqualFold(instance.NetworkType, r.NetworkType) {
+		return false
+	}
+	return true
+}
+
+// readRentalSpecFile reads and checks a spec file, in YAML or JSON. A path
+// of "-" reads standard input.
+func readRentalSpecFile(path string) (rentalSpecFile, error) {
+	var spec rentalSpecFile
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return spec, fmt.Errorf("error reading spec file: %w", err)
+	}
+
+	// JSON is also YAML, and unknown fields are most likely typos
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return spec, usageErrorf("invalid spec file %s: %v", path, err)
+	}
+
+	if spec.Name == "" && path != "-" {
+		spec.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := spec.validate(); err != nil {
+		return spec, usageErrorf("invalid spec file %s: %v", path, err)
+	}
+	return spec, nil
+}
+
+// validate checks the spec and fills in defaults
+func (s *rentalSpecFile) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required when the spec is read from standard input")
+	}
+	if !isSpecName(s.Name) {
+		return fmt.Errorf("name '%s' may only contain letters, digits, '-', '_' and '.'", s.Name)
+	}
+
+	seen := map[string]bool{}
+	for i := range s.Rentals {
+		rental := &s.Rentals[i]
+		if rental.Name == "" {
+			return fmt.Errorf("rental %d has no name", i+1)
+		}
+		if !isSpecName(rental.Name) {
+			return fmt.Errorf("rental name '%s' may only contain letters, digits, '-', '_' and '.'", rental.Name)
+		}
+		if seen[rental.Name] {
+			return fmt.Errorf("rental name '%s' is used more than once", rental.Name)
+		}
+		seen[rental.Name] = true
+
+		if err := rental.validate(); err != nil {
+			return fmt.Errorf("rental '%s': %v", rental.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a rental and fills in defaults
+func (r *rentalSpec) validate() error {
+	if r.count() < 0 {
+		return fmt.Errorf("count cannot be negative")
+	}
+	if r.GPUCount < 0 {
+		return fmt.Errorf("gpuCount cannot be negative")
+	}
+
+	isSpot := r.Type == string(hyperbolic.InstanceTypeSpot)
+	switch hyperbolic.InstanceType(r.Type) {
+	case hyperbolic.InstanceTypeSpot, hyperbolic.InstanceTypeVirtualMachine:
+		if r.GPUCount == 0 {
+			r.GPUCount = 1
+		}
+	case hyperbolic.InstanceTypeBareMetal:
+		if r.GPUCount == 0 || r.GPUCount%8 != 0 {
+			return fmt.Errorf("gpuCount must be a multiple of 8 for bare-metal rentals")
+		}
+		if r.NetworkType != "ethernet" && r.NetworkType != "infiniband" {
+			return fmt.Errorf("networkType must be 'ethernet' or 'infiniband' for bare-metal rentals")
+		}
+	default:
+		return fmt.Errorf("type must be 'spot', 'virtual-machine' or 'bare-metal'")
+	}
+
+	if !isSpot {
+		for _, field := range []struct {
+			name string
+			set  bool
+		}{
+			{"gpuModel", r.GPUModel != ""},
+			{"region", r.Region != ""},
+			{"cluster", r.Cluster != ""},
+			{"maxPrice", r.MaxPrice != 0},
+			{"ports", len(r.Ports) > 0},
+		} {
+			if field.set {
+				return fmt.Errorf("%s can only be set for spot rentals", field.name)
+			}
+		}
+	}
+	if r.Type != string(hyperbolic.InstanceTypeBareMetal) && r.NetworkType != "" {
+		return fmt.Errorf("networkType can only be set for bare-metal rentals")
+	}
+
+	if r.MaxPrice < 0 {
+		return fmt.Errorf("maxPrice cannot be negative")
+	}
+	if _, ok := maxPriceCents(r.MaxPrice); !ok {
+		return fmt.Errorf("maxPrice %g is less than a cent; it is in USD per GPU per hour, e.g. 0.5", r.MaxPrice)
+	}
+	if len(r.Ports) > 2 {
+		return fmt.Errorf("at most 2 ports can be exposed")
+	}
+	for _, port := range r.Ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("port %d is out of range (1-65535)", port)
+		}
+	}
+	return nil
+}
+
+// isSpecName reports whether name can be used as a spec or rental name,
+// which also names the state file
+func isSpecName(name string) bool {
+	return name != "." && name != ".." && strings.Trim(name, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.") == ""
+}
+
+// applyState records the rentals 'hyperbolic apply' made for a spec. The
+// API cannot tag rentals, so this file is what marks them as managed.
+type applyState struct {
+	// Rentals maps the rental names of the spec to the IDs of the
+	// instances rented for them, oldest first
+	Rentals map[string][]string `json:"rentals"`
+}
+
+// applyStatePath returns the state file of a spec. State is kept per profile,
+// since each profile may be a different account.
+func applyStatePath(specName string) (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	config, err := loadOrCreateConfig()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "apply", config.ActiveProfileName(), specName+".json"), nil
+}
+
+// loadApplyState reads a state file, which is empty if it does not exist yet
+func loadApplyState(path string) (applyState, error) {
+	state := applyState{Rentals: map[string][]string{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read apply state: %v", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse apply state %s: %v", path, err)
+	}
+	if state.Rentals == nil {
+		state.Rentals = map[string][]string{}
+	}
+	return state, nil
+}
+
+// save writes the state file, removing it once no rentals are managed
+func (s applyState) save(path string) error {
+	for name, ids := range s.Rentals {
+		if len(ids) == 0 {
+			delete(s.Rentals, name)
+		}
+	}
+	if len(s.Rentals) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove apply state: %v", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to save apply state: %v", err)
+	}
+	return nil
+}
+
+// remove forgets an instance
+func (s applyState) remove(instanceID string) {
+	for name, ids := range s.Rentals {
+		var kept []string
+		for _, id := range ids {
+			if id != instanceID {
+				kept = append(kept, id)
+			}
+		}
+		s.Rentals[name] = kept
+	}
+}
@@ -4,8 +4,13 @@ Copyright © 2025 Hyperbolic Labs
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
 	"github.com/spf13/cobra"
 )
 
@@ -13,7 +18,7 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "hyperbolic",
 	Short: "Hyperbolic CLI for renting remote GPU instances",
-	Long:  `Hyperbolic CLI allows you to rent remote GPU instances on Hyperbolic. 
+	Long: `Hyperbolic CLI allows you to rent remote GPU instances on Hyperbolic. 
 	Please create an account at https://app.hyperbolic.ai/ to get started, and make sure to upload your SSH public key in settings.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
@@ -23,21 +28,38 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	err := rootCmd.ExecuteContext(context.Background())
 	if err != nil {
-		os.Exit(1)
+		var statusErr *ExitStatusError
+		if !errors.As(err, &statusErr) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
 	// Hide the completion command from help output
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
-	
+
+	// Errors are printed to stderr by Execute, which also picks the exit code
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &UsageError{Err: fmt.Errorf("%v\nRun '%s --help' for usage.", err, cmd.CommandPath())}
+	})
+
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.hyperbolic-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&apiKeyFlag, "api-key", "", "API key to use for this command (env: HYPERBOLIC_API_KEY)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Configuration profile to use (env: HYPERBOLIC_PROFILE)")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "timeout", hyperbolic.DefaultTimeout, "Timeout for each API request (0 disables)")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "retries", hyperbolic.DefaultMaxRetries, "Number of times to retry failed API requests")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format: "+printer.Formats+" (default table)")
+	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", "", "Hyperbolic API base URL (env: HYPERBOLIC_API_URL, default: "+hyperbolic.DefaultBaseURL+")")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
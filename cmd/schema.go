@@ -0,0 +1,342 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+// outputAPIVersion identifies the schema of structured (-o json, yaml,
+// go-template and jsonpath) output. The types below are the CLI's public
+// output format and are described by the JSON Schema files in schema/v1.
+// Adding fields is compatible; renaming, removing or changing the meaning of
+// a field requires a new version.
+const outputAPIVersion = "cli.hyperbolic.xyz/v1"
+
+// Kinds of structured output documents
+const (
+	kindInstance            = "Instance"
+	kindInstanceList        = "InstanceList"
+	kindAccount             = "Account"
+	kindMarketplaceNodeList = "MarketplaceNodeList"
+	kindOnDemandOfferList   = "OnDemandOfferList"
+	kindClusterEnv          = "ClusterEnv"
+	kindRentalPlan          = "RentalPlan"
+)
+
+// typeMeta is the envelope at the top of every output document
+type typeMeta struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+func newTypeMeta(kind string) typeMeta {
+	return typeMeta{APIVersion: outputAPIVersion, Kind: kind}
+}
+
+type instanceListOutput struct {
+	typeMeta
+	Items []instanceOutput `json:"items"`
+}
+
+type instanceDocument struct {
+	typeMeta
+	instanceOutput
+}
+
+// instanceOutput is the normalized form of a spot, virtual machine or
+// bare-metal rental
+type instanceOutput struct {
+	ID              string       `json:"id"`
+	Type            string       `json:"type"`
+	Status          string       `json:"status"`
+	GPU             gpuOutput    `json:"gpu"`
+	Nodes           []nodeOutput `json:"nodes"`
+	Ports           []portOutput `json:"ports"`
+	PricePerHourUSD float64      `json:"pricePerHourUSD"`
+	CreatedAt       *time.Time   `json:"createdAt,omitempty"`
+	StartedAt       *time.Time   `json:"startedAt,omitempty"`
+	EndedAt         *time.Time   `json:"endedAt,omitempty"`
+}
+
+type gpuOutput struct {
+	Model string `json:"model"`
+	// Count is the total number of GPUs across all nodes
+	Count   int `json:"count"`
+	PerNode int `json:"perNode"`
+}
+
+type nodeOutput struct {
+	PublicIP  string     `json:"publicIP,omitempty"`
+	PrivateIP string     `json:"privateIP,omitempty"`
+	SSH       *sshOutput `json:"ssh,omitempty"`
+}
+
+type sshOutput struct {
+	User    string `json:"user"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Command string `json:"command"`
+}
+
+type portOutput struct {
+	Port         int    `json:"port"`
+	InternalPort int    `json:"internalPort,omitempty"`
+	Protocol     string `json:"protocol,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+type accountOutput struct {
+	typeMeta
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	// Credits is the balance in cents
+	Credits    int     `json:"credits"`
+	BalanceUSD float64 `json:"balanceUSD"`
+}
+
+type marketplaceNodeListOutput struct {
+	typeMeta
+	Items []marketplaceNodeOutput `json:"items"`
+}
+
+// marketplaceNodeOutput is a spot node listed on the marketplace
+type marketplaceNodeOutput struct {
+	ID                 string               `json:"id"`
+	Cluster            string               `json:"cluster"`
+	Region             string               `json:"region"`
+	Status             string               `json:"status"`
+	GPU                marketplaceGPUOutput `json:"gpu"`
+	CPU                marketplaceCPUOutput `json:"cpu"`
+	RAMGB              int                  `json:"ramGB"`
+	StorageGB          int                  `json:"storageGB"`
+	PricePerGPUHourUSD float64              `json:"pricePerGPUHourUSD"`
+	SupplierID         string               `json:"supplierID,omitempty"`
+}
+
+type marketplaceGPUOutput struct {
+	Model     string `json:"model"`
+	MemoryMB  int    `json:"memoryMB"`
+	Interface string `json:"interface,omitempty"`
+	Total     int    `json:"total"`
+	Available int    `json:"available"`
+}
+
+type marketplaceCPUOutput struct {
+	Model string `json:"model"`
+	Cores int    `json:"cores"`
+}
+
+type onDemandOfferListOutput struct {
+	typeMeta
+	Items []onDemandOfferOutput `json:"items"`
+}
+
+// onDemandOfferOutput is an on-demand configuration that can be rented
+type onDemandOfferOutput struct {
+	Type               string  `json:"type"`
+	NetworkType        string  `json:"networkType,omitempty"`
+	GPUModel           string  `json:"gpuModel"`
+	GPUCounts          []int   `json:"gpuCounts"`
+	PricePerGPUHourUSD float64 `json:"pricePerGPUHourUSD"`
+}
+
+// clusterEnvOutput is the launcher settings for distributed training across
+// the nodes of an instance
+type clusterEnvOutput struct {
+	typeMeta
+	InstanceID   string              `json:"instanceId"`
+	NetworkType  string              `json:"networkType"`
+	MasterAddr   string              `json:"masterAddr"`
+	MasterPort   int                 `json:"masterPort"`
+	NNodes       int                 `json:"nnodes"`
+	NProcPerNode int                 `json:"nprocPerNode"`
+	WorldSize    int                 `json:"worldSize"`
+	Nodes        []clusterNodeOutput `json:"nodes"`
+	// Env holds the NCCL variables for the network type
+	Env map[string]string `json:"env"`
+}
+
+type clusterNodeOutput struct {
+	Rank     int    `json:"rank"`
+	Address  string `json:"address"`
+	PublicIP string `json:"publicIP,omitempty"`
+	Slots    int    `json:"slots"`
+}
+
+// rentalPlanOutput is the changes 'hyperbolic apply' would make to the
+// rentals of a spec file
+type rentalPlanOutput struct {
+	typeMeta
+	Name    string                   `json:"name"`
+	Actions []rentalPlanActionOutput `json:"actions"`
+	// Hourly costs of the rentals managed for the spec, before and after
+	// the changes
+	CurrentCostPerHourUSD float64 `json:"currentCostPerHourUSD"`
+	PlannedCostPerHourUSD float64 `json:"plannedCostPerHourUSD"`
+	CostDeltaPerHourUSD   float64 `json:"costDeltaPerHourUSD"`
+}
+
+type rentalPlanActionOutput struct {
+	Action string `json:"action"`
+	Rental string `json:"rental"`
+	Type   string `json:"type"`
+	// InstanceID is empty for rentals to create
+	InstanceID  string `json:"instanceId,omitempty"`
+	GPUModel    string `json:"gpuModel,omitempty"`
+	GPUCount    int    `json:"gpuCount"`
+	NetworkType string `json:"networkType,omitempty"`
+	// Cluster, Node and Region are where a spot rental will be made
+	Cluster         string  `json:"cluster,omitempty"`
+	Node            string  `json:"node,omitempty"`
+	Region          string  `json:"region,omitempty"`
+	PricePerHourUSD float64 `json:"pricePerHourUSD"`
+	// Reason says why an instance the spec does not want is kept or
+	// terminated
+	Reason string `json:"reason,omitempty"`
+	// Error says why a rental cannot be created now
+	Error string `json:"error,omitempty"`
+}
+
+func newInstanceListOutput(instances []hyperbolic.Instance) instanceListOutput {
+	list := instanceListOutput{typeMeta: newTypeMeta(kindInstanceList), Items: []instanceOutput{}}
+	for _, instance := range instances {
+		list.Items = append(list.Items, newInstanceOutput(instance))
+	}
+	return list
+}
+
+func newInstanceDocument(instance instanceOutput) instanceDocument {
+	return instanceDocument{typeMeta: newTypeMeta(kindInstance), instanceOutput: instance}
+}
+
+func newInstanceOutput(instance hyperbolic.Instance) instanceOutput {
+	output := instanceOutput{
+		ID:     instance.ID,
+		Type:   string(instance.Type),
+		Status: instance.Status,
+		GPU: gpuOutput{
+			Model:   instance.GPUModel,
+			Count:   instance.GPUCount,
+			PerNode: instance.GPUsPerNode,
+		},
+		Nodes:           []nodeOutput{},
+		Ports:           []portOutput{},
+		PricePerHourUSD: instance.CostPerHour,
+		CreatedAt:       timestampPtr(instance.CreatedAt),
+		StartedAt:       timestampPtr(instance.StartedAt),
+		EndedAt:         timestampPtr(instance.EndedAt),
+	}
+	for _, node := range instance.Nodes {
+		output.Nodes = append(output.Nodes, nodeOutput{
+			PublicIP:  node.PublicIP,
+			PrivateIP: node.PrivateIP,
+			SSH:       (*sshOutput)(node.SSH),
+		})
+	}
+	for _, port := range instance.Ports {
+		output.Ports = append(output.Ports, portOutput(port))
+	}
+	return output
+}
+
+// timestampPtr returns nil for the zero time, so unknown timestamps are
+// omitted
+func timestampPtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func newAccountOutput(user hyperbolic.UserResponse, balance hyperbolic.BalanceResponse) accountOutput {
+	return accountOutput{
+		typeMeta:   newTypeMeta(kindAccount),
+		ID:         user.ID,
+		Email:      user.Email,
+		Name:       user.Name,
+		Credits:    balance.Credits,
+		BalanceUSD: float64(balance.Credits) / 100.0,
+	}
+}
+
+func newMarketplaceNodeListOutput(instances []hyperbolic.MarketplaceInstance) marketplaceNodeListOutput {
+	list := marketplaceNodeListOutput{typeMeta: newTypeMeta(kindMarketplaceNodeList), Items: []marketplaceNodeOutput{}}
+	for _, instance := range instances {
+		node := marketplaceNodeOutput{
+			ID:                 instance.ID,
+			Cluster:            instance.ClusterName,
+			Region:             instance.Location.Region,
+			Status:             instance.Status,
+			PricePerGPUHourUSD: float64(instance.Pricing.Price.Amount) / 100.0,
+			SupplierID:         instance.SupplierID,
+			GPU: marketplaceGPUOutput{
+				Model:     instance.GPU().Model,
+				MemoryMB:  instance.GPU().RAM,
+				Interface: instance.GPU().Interface,
+				Total:     instance.GpusTotal,
+				Available: instance.AvailableGPUs(),
+			},
+			CPU:       marketplaceCPUOutput{Model: instance.CPU().Model, Cores: instance.CPU().VirtualCores},
+			RAMGB:     instance.RAMGB(),
+			StorageGB: instance.StorageGB(),
+		}
+		list.Items = append(list.Items, node)
+	}
+	return list
+}
+
+func newOnDemandOfferListOutput(vmOptions hyperbolic.VirtualMachineOptions, bareMetalOptions hyperbolic.BareMetalOptions) onDemandOfferListOutput {
+	list := onDemandOfferListOutput{typeMeta: newTypeMeta(kindOnDemandOfferList), Items: []onDemandOfferOutput{}}
+
+	// Virtual machine sizes sharing a price are one offer
+	options := slices.Clone(vmOptions)
+	slices.SortStableFunc(options, func(a, b hyperbolic.VirtualMachineOption) int {
+		return cmp.Compare(a.GPUCount, b.GPUCount)
+	})
+	offers := map[float64]int{}
+	for _, option := range options {
+		i, ok := offers[option.CostPerHour]
+		if !ok {
+			i = len(list.Items)
+			offers[option.CostPerHour] = i
+			list.Items = append(list.Items, onDemandOfferOutput{
+				Type:               string(hyperbolic.InstanceTypeVirtualMachine),
+				GPUModel:           onDemandGPUModel,
+				PricePerGPUHourUSD: option.CostPerHour,
+			})
+		}
+		list.Items[i].GPUCounts = append(list.Items[i].GPUCounts, option.GPUCount)
+	}
+
+	for _, network := range []struct {
+		name   string
+		option hyperbolic.BareMetalNetworkOption
+	}{
+		{"ethernet", bareMetalOptions.Ethernet},
+		{"infiniband", bareMetalOptions.Infiniband},
+	} {
+		if network.option.GPUCount == 0 {
+			continue
+		}
+		// Bare metal is rented in multiples of 8 GPUs
+		var counts []int
+		for count := 8; count <= network.option.GPUCount; count += 8 {
+			counts = append(counts, count)
+		}
+		list.Items = append(list.Items, onDemandOfferOutput{
+			Type:               string(hyperbolic.InstanceTypeBareMetal),
+			NetworkType:        network.name,
+			GPUModel:           onDemandGPUModel,
+			GPUCounts:          counts,
+			PricePerGPUHourUSD: network.option.CostPerHour,
+		})
+	}
+	return list
+}
@@ -4,157 +4,147 @@ Copyright © 2025 Hyperbolic Labs
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
+	"cmp"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
+	"math"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/olekukonko/tablewriter"
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
 	"github.com/spf13/cobra"
 )
 
-// Response structure for the Hyperbolic API
-type MarketplaceResponse struct {
-	Instances []Instance `json:"instances"`
-}
-
-type Instance struct {
-	ID           string   `json:"id"`
-	Status       string   `json:"status"`
-	Hardware     Hardware `json:"hardware"`
-	GpusTotal    int      `json:"gpus_total"`
-	GpusReserved int      `json:"gpus_reserved"`
-	Location     Location `json:"location"`
-	Pricing      Pricing  `json:"pricing"`
-	ClusterName  string   `json:"cluster_name"`
-	SupplierID   string   `json:"supplier_id"`
-}
-
-type Hardware struct {
-	CPUs    []CPU     `json:"cpus"`
-	GPUs    []GPU     `json:"gpus"`
-	Storage []Storage `json:"storage"`
-	RAM     []RAM     `json:"ram"`
-}
-
-type CPU struct {
-	Model        string `json:"model"`
-	VirtualCores int    `json:"virtual_cores"`
-}
-
-type GPU struct {
-	Model     string `json:"model"`
-	RAM       int    `json:"ram"`
-	Interface string `json:"interface"`
-}
-
-type Storage struct {
-	Capacity int `json:"capacity"`
-}
-
-type RAM struct {
-	Capacity int `json:"capacity"`
-}
-
-type Location struct {
-	Region string `json:"region"`
-}
-
-type Pricing struct {
-	Price Price `json:"price"`
-}
-
-type Price struct {
-	Amount int    `json:"amount"`
-	Period string `json:"period"`
-	Agent  string `json:"agent"`
-}
-
 // spotCmd represents the spot command
 var spotCmd = &cobra.Command{
 	Use:   "spot",
 	Short: "View available spot compute resources.",
 	Long:  `View all available spot compute resources.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		jsonFormat, _ := cmd.Flags().GetBool("json")
+	Example: `  hyperbolic spot --gpu-model h100 --min-gpus 4 --max-price 2 --region us-east
+  hyperbolic spot --gpu-interface sxm --min-ram 512 --all
+  hyperbolic spot --gpu-model h100 --watch=30s`,
+	Args: watchArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		showAll, _ := cmd.Flags().GetBool("all")
 
-		response, err := callHyperbolicAPI()
+		format, err := selectedOutput(cmd)
 		if err != nil {
-			fmt.Printf("Error calling Hyperbolic API: %v\n", err)
-			return
+			return err
 		}
 
-		// Parse the JSON response
-		var marketplaceData MarketplaceResponse
-		err = json.Unmarshal([]byte(response), &marketplaceData)
+		filter, err := marketplaceFilterFromFlags(cmd)
 		if err != nil {
-			fmt.Printf("Error parsing API response: %v\n", err)
-			return
+			return err
 		}
 
-		if jsonFormat {
-			// If json flag is set, print raw JSON response
-			fmt.Println(response)
-		} else {
-			// Otherwise, format as a table
-			printInstancesTable(marketplaceData.Instances, showAll)
+		builder, err := selectListColumns(cmd, marketplaceTableBuilder)
+		if err != nil {
+			return err
 		}
-	},
-}
 
-func callHyperbolicAPI() (string, error) {
-	url := "https://api.hyperbolic.xyz/v1/marketplace"
+		interval, err := watchInterval(cmd, format)
+		if err != nil {
+			return err
+		}
 
-	// Create request payload
-	payload := map[string]interface{}{  "filters": map[string]interface{}{} }
+		client := newPublicClient()
+		render := func(ctx context.Context, w io.Writer, history *tableHistory) error {
+			marketplaceData, err := client.ListMarketplace(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("error calling Hyperbolic API: %w", err)
+			}
+
+			instances := filterMarketplaceInstances(marketplaceData.Instances, filter, showAll)
+			if err := sortListItems(cmd, marketplaceTableBuilder, instances); err != nil {
+				return err
+			}
+			table := builder.Table(instances)
+			if !format.IsTable() {
+				return format.Print(w, newMarketplaceNodeListOutput(instances), table)
+			}
+
+			history.highlight("spot", table)
+			fmt.Fprintln(w, "Prices are shown per GPU per hour in USD.")
+			fmt.Fprintln(w, "For rental options, run: `hyperbolic rent spot --help`")
+			table.Render(w, format.Wide())
+
+			// Show count of available instances
+			fmt.Fprintf(w, "\nShowing %d instances with available GPUs.\n", len(instances))
+			if matching := filterMarketplaceInstances(marketplaceData.Instances, filter, true); !showAll && len(matching) > len(instances) {
+				fmt.Fprintf(w, "Use --all flag to show all %d instances.\n", len(matching))
+			}
+			return nil
+		}
 
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("error marshalling JSON: %v", err)
-	}
+		if interval > 0 {
+			return watch(cmd.Context(), interval, watchCommandLine(), render)
+		}
+		return render(cmd.Context(), os.Stdout, nil)
+	},
+}
 
-	// Create HTTP request
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return "", fmt.Errorf("error creating request: %v", err)
+// marketplaceFilterFromFlags builds a marketplace filter from the spot
+// filtering flags
+func marketplaceFilterFromFlags(cmd *cobra.Command) (hyperbolic.MarketplaceFilter, error) {
+	var filter hyperbolic.MarketplaceFilter
+	filter.GPUModel, _ = cmd.Flags().GetString("gpu-model")
+	filter.GPUInterface, _ = cmd.Flags().GetString("gpu-interface")
+	filter.Region, _ = cmd.Flags().GetString("region")
+	filter.Cluster, _ = cmd.Flags().GetString("cluster")
+	filter.MinGPUs, _ = cmd.Flags().GetInt("min-gpus")
+	filter.MinRAMGB, _ = cmd.Flags().GetInt("min-ram")
+	filter.MinStorageGB, _ = cmd.Flags().GetInt("min-storage")
+	filter.MinCPUCores, _ = cmd.Flags().GetInt("min-cpu-cores")
+	maxPrice, _ := cmd.Flags().GetFloat64("max-price")
+
+	for name, value := range map[string]int{
+		"min-gpus":      filter.MinGPUs,
+		"min-ram":       filter.MinRAMGB,
+		"min-storage":   filter.MinStorageGB,
+		"min-cpu-cores": filter.MinCPUCores,
+	} {
+		if value < 0 {
+			return filter, usageErrorf("--%s cannot be negative", name)
+		}
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error sending request: %v", err)
+	if maxPrice < 0 {
+		return filter, usageErrorf("--max-price cannot be negative")
 	}
-	defer resp.Body.Close()
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("error reading response: %v", err)
+	var ok bool
+	if filter.MaxPrice, ok = maxPriceCents(maxPrice); !ok {
+		return filter, usageErrorf("--max-price %g is less than a cent; it is in USD per GPU per hour, e.g. 0.5", maxPrice)
 	}
+	return filter, nil
+}
 
-	return string(body), nil
+// maxPriceCents converts a maximum price per GPU per hour in USD to the
+// cents of MarketplaceFilter.MaxPrice. It returns false for a price above 0
+// that rounds to 0 cents, which would otherwise mean no limit at all.
+func maxPriceCents(dollars float64) (int, bool) {
+	cents := int(math.Round(dollars * 100))
+	return cents, cents > 0 || dollars <= 0
 }
 
-func printInstancesTable(instances []Instance, showAll bool) {
-	// Filter instances to only those with available GPUs, unless showAll is true
-	var filteredInstances []Instance
+// filterMarketplaceInstances returns the instances matching filter that have
+// available GPUs, unless showAll is set, sorted by cheapest price first, then
+// GPU model and node ID. Use --sort-by to choose another order.
+func filterMarketplaceInstances(instances []hyperbolic.MarketplaceInstance, filter hyperbolic.MarketplaceFilter, showAll bool) []hyperbolic.MarketplaceInstance {
+	filteredInstances := []hyperbolic.MarketplaceInstance{}
 	for _, instance := range instances {
-		availableGPUs := instance.GpusTotal - instance.GpusReserved
-		if showAll || availableGPUs > 0 {
+		if !filter.Match(instance) {
+			continue
+		}
+		if showAll || instance.AvailableGPUs() > 0 {
 			filteredInstances = append(filteredInstances, instance)
 		}
 	}
 
-	// Sort instances by cheapest price first, then by GPU model
-	sort.Slice(filteredInstances, func(i, j int) bool {
+	sort.SliceStable(filteredInstances, func(i, j int) bool {
 		priceI := filteredInstances[i].Pricing.Price.Amount
 		priceJ := filteredInstances[j].Pricing.Price.Amount
 
@@ -163,84 +153,101 @@ func printInstancesTable(instances []Instance, showAll bool) {
 			return priceI < priceJ
 		}
 
-		// If same price, sort by GPU model
-		modelI := getGPUModel(filteredInstances[i])
-		modelJ := getGPUModel(filteredInstances[j])
-		return strings.Compare(modelI, modelJ) < 0
-	})
-
-	// Create a new table
-	table := tablewriter.NewWriter(os.Stdout)
-	
-	// Set header
-	table.Header(
-		"GPU MODEL", "COUNT", "PRICE", "CLUSTER", "NODE",
-		"CPU CORES", "RAM (GB)", "STORAGE (GB)", "REGION",
-	)
-
-	// Add rows
-	for _, instance := range filteredInstances {
-		var gpuModel string
-		if len(instance.Hardware.GPUs) > 0 {
-			gpuModel = instance.Hardware.GPUs[0].Model
-		} else {
-			gpuModel = "N/A"
-		}
-
-		var cpuCores int
-		if len(instance.Hardware.CPUs) > 0 {
-			cpuCores = instance.Hardware.CPUs[0].VirtualCores
+		// If same price, sort by GPU model, then by node ID so the order
+		// does not change between runs
+		if modelI, modelJ := filteredInstances[i].GPU().Model, filteredInstances[j].GPU().Model; modelI != modelJ {
+			return modelI < modelJ
 		}
-
-		var ramGB int
-		if len(instance.Hardware.RAM) > 0 {
-			ramGB = instance.Hardware.RAM[0].Capacity
-		}
-
-		var storageGB int
-		if len(instance.Hardware.Storage) > 0 {
-			storageGB = instance.Hardware.Storage[0].Capacity
-		}
-
-		availableGPUs := instance.GpusTotal - instance.GpusReserved
-
-		// Convert cents to dollars
-		dollarAmount := float64(instance.Pricing.Price.Amount) / 100.0
-
-		table.Append(
-			gpuModel,
-			fmt.Sprintf("%d/%d", availableGPUs, instance.GpusTotal),
-			fmt.Sprintf("$%.2f", dollarAmount),
-			instance.ClusterName,
-			instance.ID,
-			fmt.Sprintf("%d", cpuCores),
-			fmt.Sprintf("%d", ramGB),
-			fmt.Sprintf("%d", storageGB),
-			instance.Location.Region,
-		)
-	}
-	fmt.Println("Prices are shown per GPU per hour in USD.")
-	fmt.Println("For rental options, run: `hyperbolic rent spot --help`")
-	// Render the table
-	table.Render()
-
-	// Show count of available instances
-	fmt.Printf("\nShowing %d instances with available GPUs.\n", len(filteredInstances))
-	if !showAll && len(instances) > len(filteredInstances) {
-		fmt.Printf("Use --all flag to show all %d instances.\n", len(instances))
-	}
+		return filteredInstances[i].ID < filteredInstances[j].ID
+	})
+	return filteredInstances
 }
 
-// getGPUModel returns the GPU model of an instance, or empty string if none
-func getGPUModel(instance Instance) string {
-	if len(instance.Hardware.GPUs) > 0 {
-		return instance.Hardware.GPUs[0].Model
-	}
-	return ""
+// marketplaceTableBuilder describes the columns and sort orders of
+// 'hyperbolic spot'
+var marketplaceTableBuilder = printer.TableBuilder[hyperbolic.MarketplaceInstance]{
+	Key: func(instance hyperbolic.MarketplaceInstance) string {
+		return instance.ID
+	},
+	Fields: []printer.Field[hyperbolic.MarketplaceInstance]{
+		{Name: "gpu-model", Header: "GPU MODEL", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			if len(instance.Hardware.GPUs) == 0 {
+				return "N/A"
+			}
+			return instance.GPU().Model
+		}},
+		{Name: "count", Header: "COUNT", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return fmt.Sprintf("%d/%d", instance.AvailableGPUs(), instance.GpusTotal)
+		}},
+		{Name: "price", Header: "PRICE", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			// Convert cents to dollars
+			return fmt.Sprintf("$%.2f", float64(instance.Pricing.Price.Amount)/100.0)
+		}},
+		{Name: "cluster", Header: "CLUSTER", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return instance.ClusterName
+		}},
+		{Name: "node", Header: "NODE", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return instance.ID
+		}},
+		{Name: "cpu-cores", Header: "CPU CORES", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return strconv.Itoa(instance.CPU().VirtualCores)
+		}},
+		{Name: "ram", Header: "RAM (GB)", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return strconv.Itoa(instance.RAMGB())
+		}},
+		{Name: "storage", Header: "STORAGE (GB)", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return strconv.Itoa(instance.StorageGB())
+		}},
+		{Name: "region", Header: "REGION", Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return instance.Location.Region
+		}},
+		{Name: "gpu-ram", Header: "GPU RAM (GB)", Wide: true, Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return strconv.Itoa(instance.GPU().RAM / 1024)
+		}},
+		{Name: "cpu-model", Header: "CPU MODEL", Wide: true, Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return instance.CPU().Model
+		}},
+		{Name: "status", Header: "STATUS", Wide: true, Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return instance.Status
+		}},
+		{Name: "supplier", Header: "SUPPLIER", Wide: true, Value: func(instance hyperbolic.MarketplaceInstance) string {
+			return instance.SupplierID
+		}},
+	},
+	SortKeys: []printer.SortKey[hyperbolic.MarketplaceInstance]{
+		{Name: "price", Compare: func(a, b hyperbolic.MarketplaceInstance) int {
+			return cmp.Compare(a.Pricing.Price.Amount, b.Pricing.Price.Amount)
+		}},
+		{Name: "gpu-model", Compare: func(a, b hyperbolic.MarketplaceInstance) int {
+			return strings.Compare(a.GPU().Model, b.GPU().Model)
+		}},
+		{Name: "available", Compare: func(a, b hyperbolic.MarketplaceInstance) int {
+			return cmp.Compare(a.AvailableGPUs(), b.AvailableGPUs())
+		}},
+		{Name: "region", Compare: func(a, b hyperbolic.MarketplaceInstance) int {
+			return strings.Compare(a.Location.Region, b.Location.Region)
+		}},
+		{Name: "ram", Compare: func(a, b hyperbolic.MarketplaceInstance) int {
+			return cmp.Compare(a.RAMGB(), b.RAMGB())
+		}},
+	},
 }
 
 func init() {
 	rootCmd.AddCommand(spotCmd)
-	spotCmd.Flags().Bool("json", false, "Output raw JSON response")
+	spotCmd.Flags().Bool("json", false, "Output as JSON (same as -o json)")
 	spotCmd.Flags().Bool("all", false, "Show all instances, including those with no available GPUs")
+	addListFlags(spotCmd, marketplaceTableBuilder)
+	addWatchFlag(spotCmd)
+
+	// Filters
+	spotCmd.Flags().String("gpu-model", "", "Only show GPU models containing this text, e.g. 'h100' or '4090'")
+	spotCmd.Flags().Int("min-gpus", 0, "Only show instances with at least this many available GPUs")
+	spotCmd.Flags().Float64("max-price", 0, "Only show instances costing at most this much per GPU per hour, in USD")
+	spotCmd.Flags().String("region", "", "Only show instances in this region, e.g. 'us-east'")
+	spotCmd.Flags().String("cluster", "", "Only show instances in this cluster")
+	spotCmd.Flags().String("gpu-interface", "", "Only show GPU interfaces containing this text, e.g. 'sxm' or 'pcie'")
+	spotCmd.Flags().Int("min-ram", 0, "Only show instances with at least this much RAM, in GB")
+	spotCmd.Flags().Int("min-storage", 0, "Only show instances with at least this much storage, in GB")
+	spotCmd.Flags().Int("min-cpu-cores", 0, "Only show instances with at least this many CPU cores")
 }
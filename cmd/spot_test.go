@@ -0,0 +1,148 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+func TestFilterMarketplaceInstances(t *testing.T) {
+	node := func(id, cluster, region, model string, price int) hyperbolic.MarketplaceInstance {
+		return hyperbolic.MarketplaceInstance{
+			ID:          id,
+			ClusterName: cluster,
+			Location:    hyperbolic.Location{Region: region},
+			Hardware:    hyperbolic.Hardware{GPUs: []hyperbolic.GPU{{Model: model}}},
+			Pricing:     hyperbolic.Pricing{Price: hyperbolic.Price{Amount: price}},
+			GpusTotal:   8,
+		}
+	}
+	instances := []hyperbolic.MarketplaceInstance{
+		node("node-c", "Cluster-EU", "EU-Central", "RTX-4090", 35),
+		node("node-a", "cluster-eu", "eu-central", "RTX-4090", 35),
+		node("node-d", "cluster-us", "us-east", "H100", 149),
+		node("node-b", "cluster-eu", "eu-central", "A100", 35),
+	}
+
+	tests := []struct {
+		name   string
+		filter hyperbolic.MarketplaceFilter
+		want   []string
+	}{
+		{"price, then GPU model, then node ID", hyperbolic.MarketplaceFilter{}, []string{"node-b", "node-a", "node-c", "node-d"}},
+		{"region ignores case", hyperbolic.MarketplaceFilter{Region: "eu-CENTRAL"}, []string{"node-b", "node-a", "node-c"}},
+		{"cluster ignores case", hyperbolic.MarketplaceFilter{Cluster: "CLUSTER-eu"}, []string{"node-b", "node-a", "node-c"}},
+		{"cluster must match exactly", hyperbolic.MarketplaceFilter{Cluster: "cluster"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The result must not depend on the order the API lists nodes in
+			for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}, {1, 3, 0, 2}} {
+				var listed []hyperbolic.MarketplaceInstance
+				for _, i := range order {
+					listed = append(listed, instances[i])
+				}
+				var got []string
+				for _, instance := range filterMarketplaceInstances(listed, tt.filter, false) {
+					got = append(got, instance.ID)
+				}
+				if len(got) != len(tt.want) {
+					t.Fatalf("listed in order %v: got %v, want %v", order, got, tt.want)
+				}
+				for i := range got {
+					if got[i] != tt.want[i] {
+						t.Fatalf("listed in order %v: got %v, want %v", order, got, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMaxPriceUnderACent(t *testing.T) {
+	newGoldenServer(t)
+	for _, args := range [][]string{
+		{"spot", "--max-price", "0.004"},
+		{"rent", "spot", "--auto", "--gpu-model", "4090", "--max-price", "0.004"},
+		{"rent", "spot", "--until-available", "--gpu-model", "4090", "--max-price", "0.001"},
+	} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			// A price that rounds to 0 cents would mean no limit at all
+			_, err := executeCLI(t, args...)
+			if exitCode(err) != ExitUsage || !strings.Contains(err.Error(), "less than a cent") {
+				t.Fatalf("expected a usage error, got %v", err)
+			}
+		})
+	}
+
+	if output := runCLI(t, "spot", "--max-price", "0.005", "-o", "json"); !strings.Contains(output, `"items": []`) {
+		t.Errorf("--max-price 0.005 should round up to a cent and match no node:\n%s", output)
+	}
+}
+
+func TestRentOnSpotNodes(t *testing.T) {
+	candidates := []hyperbolic.MarketplaceInstance{
+		{ID: "mock-h100-1", ClusterName: "mock-cluster-east"},
+		{ID: "mock-4090-1", ClusterName: "mock-cluster-eu"},
+	}
+
+	tests := []struct {
+		name string
+		// status and message are the first node's rejection
+		status    int
+		message   string
+		wantNodes []string
+		wantError string
+	}{
+		{name: "a node without free GPUs is skipped", status: http.StatusBadRequest, message: "not enough GPUs available on node", wantNodes: []string{"mock-h100-1", "mock-4090-1"}},
+		{name: "a conflict is skipped", status: http.StatusConflict, message: "conflict", wantNodes: []string{"mock-h100-1", "mock-4090-1"}},
+		{name: "an invalid request stops at once", status: http.StatusBadRequest, message: "invalid image", wantNodes: []string{"mock-h100-1"}, wantError: "invalid image"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []string
+			client := newInterceptClient(t, func(w http.ResponseWriter, r *http.Request, backend http.Handler) {
+				if r.Method+" "+r.URL.Path != routeRentSpot {
+					backend.ServeHTTP(w, r)
+					return
+				}
+				var request hyperbolic.RentRequest
+				json.NewDecoder(r.Body).Decode(&request)
+				nodes = append(nodes, request.NodeName)
+				if len(nodes) == 1 {
+					http.Error(w, `{"message": "`+tt.message+`"}`, tt.status)
+					return
+				}
+				body, _ := json.Marshal(request)
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				backend.ServeHTTP(w, r)
+			})
+
+			_, request, err := rentOnSpotNodes(context.Background(), client, candidates, hyperbolic.RentRequest{GpuCount: 1})
+			if strings.Join(nodes, ",") != strings.Join(tt.wantNodes, ",") {
+				t.Errorf("tried nodes %v, want %v", nodes, tt.wantNodes)
+			}
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if request.NodeName != "mock-4090-1" {
+				t.Errorf("rented on %s, want mock-4090-1", request.NodeName)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+// sshCmd represents the ssh command
+var sshCmd = &cobra.Command{
+	Use:   "ssh <instance-id> [-- ssh-args...]",
+	Short: "Open an SSH session to an instance.",
+	Long: `Connect to a rented instance with the system ssh client, using the SSH details reported for the instance.
+
+Arguments after '--' are passed to ssh, for options such as port forwards or a command to run on the instance.
+
+EXAMPLES:
+  hyperbolic ssh 1234
+  hyperbolic ssh 1234 -- -L 8888:localhost:8888
+  hyperbolic ssh 1234 --node 2 -- nvidia-smi
+  hyperbolic ssh mock-1001 --identity ~/.ssh/hyperbolic_ed25519`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		instanceID := args[0]
+		extraArgs := args[1:]
+		if len(extraArgs) > 0 && cmd.ArgsLenAtDash() != 1 {
+			return usageErrorf("arguments for ssh must follow '--', e.g. hyperbolic ssh %s -- %s", instanceID, extraArgs[0])
+		}
+
+		nodeNumber, _ := cmd.Flags().GetInt("node")
+		identity, _ := cmd.Flags().GetString("identity")
+		if nodeNumber < 1 {
+			return usageErrorf("--node must be 1 or more")
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		instance, err := findInstance(cmd.Context(), client, instanceID)
+		if err != nil {
+			return err
+		}
+
+		endpoint, err := instanceSSHEndpoint(instance, nodeNumber)
+		if err != nil {
+			return err
+		}
+		if len(instance.Nodes) > 1 && !cmd.Flags().Changed("node") {
+			fmt.Fprintf(os.Stderr, "Instance %s has %d nodes; connecting to node 1. Use --node to choose another.\n", instance.ID, len(instance.Nodes))
+		}
+
+		return runSSH(sshArgs(endpoint, identity, extraArgs))
+	},
+}
+
+// instanceSSHEndpoint returns the SSH endpoint of an instance's node,
+// numbered from 1 as in 'hyperbolic instances <instance-id>'
+func instanceSSHEndpoint(instance hyperbolic.Instance, nodeNumber int) (hyperbolic.SSHEndpoint, error) {
+	if nodeNumber > len(instance.Nodes) {
+		if len(instance.Nodes) == 0 {
+			return hyperbolic.SSHEndpoint{}, noSSHEndpointError(instance)
+		}
+		return hyperbolic.SSHEndpoint{}, usageErrorf("instance %s has %d node(s), so there is no node %d", instance.ID, len(instance.Nodes), nodeNumber)
+	}
+	node := instance.Nodes[nodeNumber-1]
+	if node.SSH == nil {
+		return hyperbolic.SSHEndpoint{}, noSSHEndpointError(instance)
+	}
+	return *node.SSH, nil
+}
+
+// noSSHEndpointError explains why an instance cannot be reached yet
+func noSSHEndpointError(instance hyperbolic.Instance) error {
+	if instance.IsStarting() {
+		return fmt.Errorf("instance %s is still %s; SSH is available when it is running", instance.ID, instance.Status)
+	}
+	return fmt.Errorf("no SSH details available for instance %s (status: %s)", instance.ID, instance.Status)
+}
+
+// sshArgs returns the arguments for ssh to connect to endpoint, with an
+// optional identity file and extra arguments after the destination
+func sshArgs(endpoint hyperbolic.SSHEndpoint, identity string, extraArgs []string) []string {
+	args := append(sshOptions(endpoint, identity), endpoint.Destination())
+	return append(args, extraArgs...)
+}
+
+// sshOptions returns the ssh options for the port of endpoint and an
+// optional identity file
+func sshOptions(endpoint hyperbolic.SSHEndpoint, identity string) []string {
+	var options []string
+	if identity != "" {
+		options = append(options, "-i", identity)
+	}
+	if endpoint.Port != 0 && endpoint.Port != 22 {
+		options = append(options, "-p", strconv.Itoa(endpoint.Port))
+	}
+	return options
+}
+
+// sshPath returns the path of the system ssh client
+func sshPath() (string, error) {
+	path, err := exec.LookPath("ssh")
+	if err != nil {
+		return "", fmt.Errorf("ssh client not found; install OpenSSH and make sure 'ssh' is on your PATH")
+	}
+	return path, nil
+}
+
+// runSSH runs the system ssh client with args attached to the terminal. If
+// ssh fails, its exit status is returned as an ExitStatusError.
+func runSSH(args []string) error {
+	path, err := sshPath()
+	if err != nil {
+		return err
+	}
+	return runAttached(path, args)
+}
+
+// runAttached runs the program at path attached to the terminal, as for
+// ssh, scp and rsync. If it fails, its exit status is returned as an
+// ExitStatusError.
+func runAttached(path string, args []string) error {
+	program := exec.Command(path, args...)
+	program.Stdin = os.Stdin
+	program.Stdout = os.Stdout
+	program.Stderr = os.Stderr
+
+	// Ctrl-C is meant for the remote session, so only the program should act
+	// on it
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	err := program.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return &ExitStatusError{Status: exitErr.ExitCode()}
+	}
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(sshCmd)
+	sshCmd.Flags().Int("node", 1, "Node to connect to, for multi-node bare-metal rentals")
+	sshCmd.Flags().StringP("identity", "i", "", "Private key file to authenticate with (passed to ssh -i)")
+}
@@ -0,0 +1,252 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+// sshConfigHeader starts the file written by 'hyperbolic ssh-config --write'
+const sshConfigHeader = `# Generated by 'hyperbolic ssh-config --write'; changes will be overwritten.
+# Run it again after renting or terminating instances to update this file.
+`
+
+// sshConfigCmd represents the ssh-config command
+var sshConfigCmd = &cobra.Command{
+	Use:   "ssh-config",
+	Short: "Generate OpenSSH config entries for your instances.",
+	Long: `Print an OpenSSH config block with a Host entry for each running instance, so tools such as ssh, scp, rsync, VS Code Remote-SSH and Ansible can connect by name:
+
+  hyperbolic-<instance-id>          the instance, or node 1 of a multi-node rental
+  hyperbolic-<instance-id>-node<N>  each node of a bare-metal rental
+
+With --write the entries are saved to ~/.ssh/config.d/hyperbolic instead, replacing the entries of instances that are gone. Include that file from the top of ~/.ssh/config:
+
+  Include config.d/hyperbolic
+
+EXAMPLES:
+  hyperbolic ssh-config >> ~/.ssh/config
+  hyperbolic ssh-config --write --identity ~/.ssh/hyperbolic_ed25519
+  ssh hyperbolic-1234`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		write, _ := cmd.Flags().GetBool("write")
+		path, _ := cmd.Flags().GetString("file")
+		identity, _ := cmd.Flags().GetString("identity")
+		if cmd.Flags().Changed("file") && !write {
+			return usageErrorf("--file can only be used with --write")
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		instances, err := client.ListInstances(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("error fetching instances: %w", err)
+		}
+
+		if !write {
+			writeSSHConfig(os.Stdout, instances, identity)
+			return nil
+		}
+
+		if path == "" {
+			if path, err = defaultSSHConfigPath(); err != nil {
+				return err
+			}
+		}
+		var buf bytes.Buffer
+		buf.WriteString(sshConfigHeader)
+		writeSSHConfig(&buf, instances, identity)
+		if err := writeFileAtomic(path, buf.Bytes(), 0600); err != nil {
+			return fmt.Errorf("failed to write SSH config: %v", err)
+		}
+
+		hosts := 0
+		for _, instance := range instances {
+			hosts += len(sshConfigHosts(instance))
+		}
+		fmt.Printf("Wrote %d host(s) to %s\n", hosts, path)
+		if !sshConfigIncludes(path) {
+			fmt.Println()
+			fmt.Println("To use them, add this line to the top of ~/.ssh/config:")
+			fmt.Printf("  Include %s\n", path)
+		}
+		return nil
+	},
+}
+
+// sshConfigHost is one Host entry of the generated config
+type sshConfigHost struct {
+	Alias    string
+	Endpoint hyperbolic.SSHEndpoint
+}
+
+// sshConfigHosts returns the Host entries for an instance: one for the
+// instance, pointing at its first node, and one per node of a bare-metal
+// rental. An instance that has ended has none, as its address may already
+// belong to someone else's rental.
+func sshConfigHosts(instance hyperbolic.Instance) []sshConfigHost {
+	if instance.HasEnded() {
+		return nil
+	}
+	var hosts []sshConfigHost
+	for i, node := range instance.Nodes {
+		if node.SSH == nil {
+			continue
+		}
+		if len(hosts) == 0 {
+			hosts = append(hosts, sshConfigHost{Alias: sshHostAlias(instance, 0), Endpoint: *node.SSH})
+		}
+		if instance.Type == hyperbolic.InstanceTypeBareMetal || len(instance.Nodes) > 1 {
+			hosts = append(hosts, sshConfigHost{Alias: sshHostAlias(instance, i+1), Endpoint: *node.SSH})
+		}
+	}
+	return hosts
+}
+
+// sshHostAlias returns the SSH config host name of an instance, or of one of
+// its nodes if node is not 0. Characters ssh does not accept in a host name
+// are replaced with '-'.
+func sshHostAlias(instance hyperbolic.Instance, node int) string {
+	alias := "hyperbolic-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' || r == '_' {
+			return r
+		}
+		return '-'
+	}, instance.ID)
+	if node > 0 {
+		alias += fmt.Sprintf("-node%d", node)
+	}
+	return alias
+}
+
+// writeSSHConfig writes the Host entries for instances to w. Instances that
+// cannot be reached yet are listed in a comment, and those that have ended
+// are left out.
+func writeSSHConfig(w io.Writer, instances []hyperbolic.Instance, identity string) {
+	for _, instance := range instances {
+		if instance.HasEnded() {
+			continue
+		}
+		hosts := sshConfigHosts(instance)
+		if len(hosts) == 0 {
+			fmt.Fprintf(w, "\n# %s instance %s: no SSH details yet (status: %s)\n", instanceTypeLabel(instance.Type), instance.ID, instance.Status)
+			continue
+		}
+
+		fmt.Fprintf(w, "\n# %s instance %s: %s, %s GPU(s)\n", instanceTypeLabel(instance.Type), instance.ID, displayGPUModel(instance.GPUModel), formatGPUCount(instance))
+		for _, host := range hosts {
+			fmt.Fprintf(w, "Host %s\n", host.Alias)
+			fmt.Fprintf(w, "    HostName %s\n", host.Endpoint.Host)
+			if host.Endpoint.User != "" {
+				fmt.Fprintf(w, "    User %s\n", host.Endpoint.User)
+			}
+			if host.Endpoint.Port != 0 {
+				fmt.Fprintf(w, "    Port %d\n", host.Endpoint.Port)
+			}
+			if identity != "" {
+				fmt.Fprintf(w, "    IdentityFile %s\n", sshConfigQuote(identity))
+				fmt.Fprintf(w, "    IdentitiesOnly yes\n")
+			}
+		}
+	}
+}
+
+// sshConfigQuote quotes a value for an SSH config file if it contains spaces
+func sshConfigQuote(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// defaultSSHConfigPath returns the file written by 'ssh-config --write'
+func defaultSSHConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".ssh", "config.d", "hyperbolic"), nil
+}
+
+// sshConfigIncludes reports whether ~/.ssh/config appears to include path
+func sshConfigIncludes(path string) bool {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(homeDir, ".ssh", "config"))
+	if err != nil {
+		return false
+	}
+
+	// Include paths may be relative to ~/.ssh or use ~
+	sshDir := filepath.Join(homeDir, ".ssh")
+	candidates := []string{path}
+	if relative, err := filepath.Rel(sshDir, path); err == nil {
+		candidates = append(candidates, relative)
+	}
+	if relative, err := filepath.Rel(homeDir, path); err == nil {
+		candidates = append(candidates, "~/"+relative)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "Include") {
+			continue
+		}
+		for _, included := range fields[1:] {
+			for _, candidate := range candidates {
+				if included == candidate {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// writeFileAtomic replaces the file at path with data, so readers never see
+// a partly written file. Missing directories are created.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(perm); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func init() {
+	rootCmd.AddCommand(sshConfigCmd)
+	sshConfigCmd.Flags().Bool("write", false, "Write the entries to a managed file instead of printing them")
+	sshConfigCmd.Flags().String("file", "", "File written by --write (default ~/.ssh/config.d/hyperbolic)")
+	sshConfigCmd.Flags().StringP("identity", "i", "", "Private key file to use for every host (IdentityFile)")
+}
@@ -0,0 +1,48 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+func TestWriteSSHConfigSkipsEnded(t *testing.T) {
+	instance := func(id, status string) hyperbolic.Instance {
+		return hyperbolic.Instance{
+			ID:     id,
+			Type:   hyperbolic.InstanceTypeSpot,
+			Status: status,
+			Nodes:  []hyperbolic.InstanceNode{{SSH: hyperbolic.ParseSSHCommand("ssh ubuntu@203.0.113.7 -p 31001")}},
+		}
+	}
+	ended := instance("ended", "running")
+	ended.EndedAt = time.Now()
+	instances := []hyperbolic.Instance{
+		instance("running", "running"),
+		instance("terminated", "terminated"),
+		ended,
+	}
+
+	var config strings.Builder
+	writeSSHConfig(&config, instances, "")
+	if !strings.Contains(config.String(), "Host hyperbolic-running\n") {
+		t.Errorf("running instance is missing:\n%s", config.String())
+	}
+	for _, id := range []string{"terminated", "ended"} {
+		if strings.Contains(config.String(), id) {
+			t.Errorf("instance %s has ended but is in the config:\n%s", id, config.String())
+		}
+	}
+
+	// The count printed by --write comes from sshConfigHosts
+	for _, instance := range instances[1:] {
+		if hosts := sshConfigHosts(instance); len(hosts) != 0 {
+			t.Errorf("instance %s has ended but has hosts %v", instance.ID, hosts)
+		}
+	}
+}
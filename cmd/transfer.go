@@ -0,0 +1,244 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+// cpCmd represents the cp command
+var cpCmd = &cobra.Command{
+	Use:   "cp <source>... <destination>",
+	Short: "Copy files to or from an instance.",
+	Long: `Copy files between this machine and a rented instance with scp. Write remote paths as <instance-id>:<path>; an empty path is the home directory.
+
+Uploads go to every node of a multi-node rental, or the nodes chosen with --nodes. Downloads come from node 1, or the node chosen with --nodes.
+
+EXAMPLES:
+  hyperbolic cp train.py 1234:~/
+  hyperbolic cp -r ./dataset 1234:/data/dataset
+  hyperbolic cp 1234:~/checkpoints/last.pt ./last.pt
+  hyperbolic cp --nodes 2 1234:/var/log/train.log .`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		return runTransfer(cmd, args, "scp", func(endpoint hyperbolic.SSHEndpoint, identity string, batch bool, sources []string, dest string) []string {
+			var scpArgs []string
+			if batch {
+				scpArgs = append(scpArgs, batchSSHArgs(cmd)...)
+			}
+			if recursive {
+				scpArgs = append(scpArgs, "-r")
+			}
+			if identity != "" {
+				scpArgs = append(scpArgs, "-i", identity)
+			}
+			if endpoint.Port != 0 && endpoint.Port != 22 {
+				scpArgs = append(scpArgs, "-P", strconv.Itoa(endpoint.Port))
+			}
+			scpArgs = append(scpArgs, sources...)
+			return append(scpArgs, dest)
+		})
+	},
+}
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync <source>... <destination>",
+	Short: "Synchronize directories with an instance using rsync.",
+	Long: `Synchronize files between this machine and a rented instance with rsync, copying only what changed. Write remote paths as <instance-id>:<path>. As with rsync, a trailing slash on a source directory copies its contents rather than the directory itself.
+
+Uploads go to every node of a multi-node rental, or the nodes chosen with --nodes. Downloads come from node 1, or the node chosen with --nodes. rsync must be installed both locally and on the instance.
+
+EXAMPLES:
+  hyperbolic sync ./project/ 1234:~/project
+  hyperbolic sync --delete --exclude .git --exclude '*.pyc' ./project/ 1234:~/project
+  hyperbolic sync 1234:~/checkpoints/ ./checkpoints`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deleteExtra, _ := cmd.Flags().GetBool("delete")
+		excludes, _ := cmd.Flags().GetStringSlice("exclude")
+		return runTransfer(cmd, args, "rsync", func(endpoint hyperbolic.SSHEndpoint, identity string, batch bool, sources []string, dest string) []string {
+			rsyncArgs := []string{"-az", "--partial"}
+			if !batch {
+				// --info=progress2 would need rsync 3.1, newer than macOS ships
+				rsyncArgs = append(rsyncArgs, "--progress")
+			}
+			if deleteExtra {
+				rsyncArgs = append(rsyncArgs, "--delete")
+			}
+			for _, pattern := range excludes {
+				rsyncArgs = append(rsyncArgs, "--exclude", pattern)
+			}
+
+			// rsync runs ssh itself, with the options given in one string
+			sshCommand := []string{"ssh"}
+			if batch {
+				sshCommand = append(sshCommand, batchSSHArgs(cmd)...)
+			}
+			sshCommand = append(sshCommand, sshOptions(endpoint, identity)...)
+			for i := range sshCommand {
+				sshCommand[i] = shellQuote(sshCommand[i])
+			}
+			rsyncArgs = append(rsyncArgs, "-e", strings.Join(sshCommand, " "))
+
+			rsyncArgs = append(rsyncArgs, sources...)
+			return append(rsyncArgs, dest)
+		})
+	},
+}
+
+// transferArgs returns the arguments for scp or rsync to copy sources to
+// dest on a node. batch is set when several nodes are copied to at once, so
+// the program cannot prompt and its output is not shown on a terminal.
+type transferArgs func(endpoint hyperbolic.SSHEndpoint, identity string, batch bool, sources []string, dest string) []string
+
+// runTransfer copies files to or from an instance with program, which is
+// scp or rsync, given the <source>... <destination> arguments of cp or sync
+func runTransfer(cmd *cobra.Command, args []string, program string, programArgs transferArgs) error {
+	instanceID, sources, dest, upload, err := parseTransferArgs(args)
+	if err != nil {
+		return err
+	}
+
+	identity, _ := cmd.Flags().GetString("identity")
+	limit, err := maxParallel(cmd)
+	if err != nil {
+		return err
+	}
+
+	path, err := exec.LookPath(program)
+	if err != nil {
+		return fmt.Errorf("%s not found; install it and make sure '%s' is on your PATH", program, program)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	instance, err := findInstance(cmd.Context(), client, instanceID)
+	if err != nil {
+		return err
+	}
+
+	targets, err := selectNodeTargets(cmd, instance)
+	if err != nil {
+		return err
+	}
+	if !upload {
+		if len(targets) > 1 && cmd.Flags().Changed("nodes") {
+			return usageErrorf("files can only be downloaded from one node at a time; choose it with --nodes N")
+		}
+		targets = targets[:1]
+	}
+
+	// Paths on the node are written as user@host:path
+	nodeArgs := func(endpoint hyperbolic.SSHEndpoint, batch bool) []string {
+		remote := func(path string) string {
+			return endpoint.Destination() + ":" + path
+		}
+		if upload {
+			return programArgs(endpoint, identity, batch, sources, remote(dest))
+		}
+		remoteSources := make([]string, len(sources))
+		for i, source := range sources {
+			remoteSources[i] = remote(source)
+		}
+		return programArgs(endpoint, identity, batch, remoteSources, dest)
+	}
+
+	// A single copy can show the program's own progress and prompts
+	if len(targets) == 1 {
+		return runAttached(path, nodeArgs(targets[0].Endpoint, false))
+	}
+
+	results := runOnNodes(cmd.Context(), targets, limit, false, os.Stdout, os.Stderr, func(ctx context.Context, target nodeTarget, stdout, stderr io.Writer) error {
+		fmt.Fprintf(stdout, "copying to %s:%s\n", target.Endpoint.Destination(), dest)
+		transfer := exec.CommandContext(ctx, path, nodeArgs(target.Endpoint, true)...)
+		transfer.Stdout = stdout
+		transfer.Stderr = stderr
+		if err := transfer.Run(); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "done")
+		return nil
+	})
+	return nodeResultsError(results, os.Stderr, program)
+}
+
+// parseTransferArgs splits the <source>... <destination> arguments of cp and
+// sync. Either the destination or every source must be on the same instance.
+func parseTransferArgs(args []string) (instanceID string, sources []string, dest string, upload bool, err error) {
+	destID, dest, destRemote := parseRemotePath(args[len(args)-1])
+	if destRemote {
+		for _, source := range args[:len(args)-1] {
+			if _, _, remote := parseRemotePath(source); remote {
+				return "", nil, "", false, usageErrorf("cannot copy between instances; copy from '%s' to this machine first", source)
+			}
+		}
+		return destID, args[:len(args)-1], dest, true, nil
+	}
+
+	for _, source := range args[:len(args)-1] {
+		id, path, remote := parseRemotePath(source)
+		if !remote {
+			return "", nil, "", false, usageErrorf("either the destination or the sources must be on an instance, written as <instance-id>:<path>")
+		}
+		if instanceID != "" && id != instanceID {
+			return "", nil, "", false, usageErrorf("all sources must be on the same instance")
+		}
+		instanceID = id
+		sources = append(sources, path)
+	}
+	return instanceID, sources, args[len(args)-1], false, nil
+}
+
+// parseRemotePath splits an <instance-id>:<path> argument. As with scp, an
+// argument with a slash before the first colon is a local path.
+func parseRemotePath(arg string) (instanceID, path string, remote bool) {
+	instanceID, path, found := strings.Cut(arg, ":")
+	if !found || instanceID == "" || strings.ContainsAny(instanceID, `/\`) {
+		return "", arg, false
+	}
+	// C:\data on Windows is a local path
+	if runtime.GOOS == "windows" && len(instanceID) == 1 {
+		return "", arg, false
+	}
+	return instanceID, path, true
+}
+
+// shellQuote quotes s for a POSIX shell, which is also how rsync splits the
+// command given with -e
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=.,/:@+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+	addNodeFlags(cpCmd)
+	addHostKeyFlag(cpCmd)
+	cpCmd.Flags().BoolP("recursive", "r", false, "Copy directories recursively")
+	cpCmd.Flags().StringP("identity", "i", "", "Private key file to authenticate with (passed to ssh -i)")
+
+	rootCmd.AddCommand(syncCmd)
+	addNodeFlags(syncCmd)
+	addHostKeyFlag(syncCmd)
+	syncCmd.Flags().Bool("delete", false, "Delete files in the destination that are not in the source")
+	syncCmd.Flags().StringSlice("exclude", nil, "Patterns of files to skip (passed to rsync --exclude)")
+	syncCmd.Flags().StringP("identity", "i", "", "Private key file to authenticate with (passed to ssh -i)")
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTransferArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		instanceID string
+		sources    []string
+		dest       string
+		upload     bool
+	}{
+		{"upload", []string{"train.py", "1234:~/"}, "1234", []string{"train.py"}, "~/", true},
+		{"upload several", []string{"a.txt", "./data/b.txt", "1234:/data"}, "1234", []string{"a.txt", "./data/b.txt"}, "/data", true},
+		{"upload to the home directory", []string{"train.py", "1234:"}, "1234", []string{"train.py"}, "", true},
+		{"download", []string{"1234:~/last.pt", "."}, "1234", []string{"~/last.pt"}, ".", false},
+		{"download several", []string{"mock-1001:a.txt", "mock-1001:b.txt", "out/"}, "mock-1001", []string{"a.txt", "b.txt"}, "out/", false},
+		{"spaces in paths", []string{"my data/file 1.txt", "1234:~/my data/"}, "1234", []string{"my data/file 1.txt"}, "~/my data/", true},
+		{"quotes in paths", []string{`it's "here".txt`, "1234:~/"}, "1234", []string{`it's "here".txt`}, "~/", true},
+		{"colon in a local path after a slash", []string{"./a:b", "1234:~/"}, "1234", []string{"./a:b"}, "~/", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instanceID, sources, dest, upload, err := parseTransferArgs(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if instanceID != tt.instanceID || !slices.Equal(sources, tt.sources) || dest != tt.dest || upload != tt.upload {
+				t.Errorf("got %q %q %q upload=%v, want %q %q %q upload=%v", instanceID, sources, dest, upload, tt.instanceID, tt.sources, tt.dest, tt.upload)
+			}
+		})
+	}
+}
+
+func TestParseTransferArgsInvalid(t *testing.T) {
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"1234:a.txt", "5678:b.txt"}, "cannot copy between instances"},
+		{[]string{"local.txt", "1234:a.txt", "5678:/data"}, "cannot copy between instances"},
+		{[]string{"a.txt", "b.txt"}, "must be on an instance"},
+		{[]string{"1234:a.txt", "local.txt", "out/"}, "must be on an instance"},
+		{[]string{"1234:a.txt", "5678:b.txt", "out/"}, "same instance"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			_, _, _, _, err := parseTransferArgs(tt.args)
+			if exitCode(err) != ExitUsage || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("got %v, want a usage error containing %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestParseRemotePath(t *testing.T) {
+	tests := []struct {
+		arg        string
+		instanceID string
+		path       string
+		remote     bool
+	}{
+		{"1234:~/data", "1234", "~/data", true},
+		{"1234:", "1234", "", true},
+		{"mock-1001:/tmp/a b", "mock-1001", "/tmp/a b", true},
+		{"1234:a:b", "1234", "a:b", true},
+		{"file.txt", "", "file.txt", false},
+		{":file.txt", "", ":file.txt", false},
+		{"./dir:name", "", "./dir:name", false},
+		{`dir\sub:name`, "", `dir\sub:name`, false},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests, struct {
+			arg        string
+			instanceID string
+			path       string
+			remote     bool
+		}{`C:\data`, "", `C:\data`, false})
+	}
+	for _, tt := range tests {
+		instanceID, path, remote := parseRemotePath(tt.arg)
+		if instanceID != tt.instanceID || path != tt.path || remote != tt.remote {
+			t.Errorf("parseRemotePath(%q) = %q, %q, %v, want %q, %q, %v", tt.arg, instanceID, path, remote, tt.instanceID, tt.path, tt.remote)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"ssh", "ssh"},
+		{"BatchMode=yes", "BatchMode=yes"},
+		{"/home/me/.ssh/id_ed25519", "/home/me/.ssh/id_ed25519"},
+		{"", "''"},
+		{"my key", "'my key'"},
+		{"it's", `'it'\''s'`},
+		{`say "hi"`, `'say "hi"'`},
+		{"$HOME", "'$HOME'"},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.value); got != tt.want {
+			t.Errorf("shellQuote(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	// The quoted words read back as the original values
+	if runtime.GOOS == "windows" {
+		return
+	}
+	for _, tt := range tests {
+		output, err := exec.Command("sh", "-c", "printf %s "+shellQuote(tt.value)).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(output) != tt.value {
+			t.Errorf("sh read %q back as %q", tt.want, output)
+		}
+	}
+}
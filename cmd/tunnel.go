@@ -0,0 +1,475 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// tunnelKeepAlive is how often ssh checks that a tunnel's connection is
+	// alive; it gives up after three unanswered checks
+	tunnelKeepAlive = 15 * time.Second
+	// tunnelMaxBackoff is the longest wait between reconnection attempts
+	tunnelMaxBackoff = 30 * time.Second
+	// tunnelStableAfter is how long a connection must last for the next drop
+	// to count as a new failure rather than a repeated one
+	tunnelStableAfter = 30 * time.Second
+	// tunnelMaxFailures is how many connection attempts in a row may fail
+	// quickly before the tunnel gives up
+	tunnelMaxFailures = 10
+)
+
+// tunnelCmd represents the tunnel command
+var tunnelCmd = &cobra.Command{
+	Use:   "tunnel <instance-id> <remote-port>[:<local-port>]...",
+	Short: "Forward local ports to services on an instance.",
+	Long: `Open SSH port forwards from this machine to services on an instance, such as Jupyter, TensorBoard or vLLM, and reconnect them if the connection drops.
+
+Each port is forwarded to the same local port unless another is given after a colon. The tunnel runs until Ctrl-C, or in the background with --background; manage background tunnels with 'hyperbolic tunnel list' and 'hyperbolic tunnel stop'.
+
+EXAMPLES:
+  hyperbolic tunnel 1234 8888                 # Jupyter on http://localhost:8888
+  hyperbolic tunnel 1234 6006:16006 8000      # TensorBoard on 16006, vLLM on 8000
+  hyperbolic tunnel 1234 8888 --background
+  hyperbolic tunnel list
+  hyperbolic tunnel stop 1234`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		instanceID := args[0]
+		forwards, err := parsePortForwards(args[1:])
+		if err != nil {
+			return err
+		}
+
+		nodeNumber, _ := cmd.Flags().GetInt("node")
+		identity, _ := cmd.Flags().GetString("identity")
+		background, _ := cmd.Flags().GetBool("background")
+		if nodeNumber < 1 {
+			return usageErrorf("--node must be 1 or more")
+		}
+
+		// A forward that cannot listen would fail on every reconnection
+		for _, forward := range forwards {
+			listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(forward.LocalPort)))
+			if err != nil {
+				return fmt.Errorf("local port %d is not available: %v", forward.LocalPort, err)
+			}
+			listener.Close()
+		}
+
+		path, err := sshPath()
+		if err != nil {
+			return err
+		}
+
+		// A background tunnel is given the endpoint found by the command that
+		// started it
+		var endpoint hyperbolic.SSHEndpoint
+		if command, _ := cmd.Flags().GetString("ssh-endpoint"); command != "" {
+			parsed := hyperbolic.ParseSSHCommand(command)
+			if parsed == nil {
+				return usageErrorf("invalid --ssh-endpoint '%s'", command)
+			}
+			endpoint = *parsed
+		} else {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			instance, err := findInstance(cmd.Context(), client, instanceID)
+			if err != nil {
+				return err
+			}
+
+			if endpoint, err = instanceSSHEndpoint(instance, nodeNumber); err != nil {
+				return err
+			}
+		}
+
+		if background {
+			return startBackgroundTunnel(cmd, instanceID, endpoint, forwards)
+		}
+
+		tunnelArgs := tunnelSSHArgs(endpoint, identity, batchSSHArgs(cmd), forwards)
+		for _, forward := range forwards {
+			fmt.Printf("Forwarding localhost:%d -> %s port %d\n", forward.LocalPort, instanceID, forward.RemotePort)
+		}
+		fmt.Println("Press Ctrl-C to stop.")
+		return runTunnel(cmd.Context(), path, tunnelArgs)
+	},
+}
+
+// tunnelListCmd represents the tunnel list command
+var tunnelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List background tunnels.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := selectedOutput(cmd)
+		if err != nil {
+			return err
+		}
+		if !format.IsTable() {
+			return usageErrorf("tunnel list only supports table output, not -o %s", format.Name)
+		}
+
+		tunnels, err := loadTunnels()
+		if err != nil {
+			return err
+		}
+		if len(tunnels) == 0 {
+			fmt.Println("No background tunnels are running.")
+			return nil
+		}
+
+		table := printer.NewTable(
+			printer.Column{Header: "INSTANCE ID"},
+			printer.Column{Header: "FORWARDS"},
+			printer.Column{Header: "PID"},
+			printer.Column{Header: "STARTED"},
+			printer.Column{Header: "LOG", Wide: true},
+		)
+		for _, tunnel := range tunnels {
+			var forwards []string
+			for _, forward := range tunnel.Forwards {
+				forwards = append(forwards, fmt.Sprintf("localhost:%d -> %d", forward.LocalPort, forward.RemotePort))
+			}
+			table.Append(tunnel.InstanceID, strings.Join(forwards, "\n"), strconv.Itoa(tunnel.PID), formatTimestamp(tunnel.StartedAt), tunnel.LogFile)
+		}
+		table.Render(os.Stdout, format.Wide())
+		return nil
+	},
+}
+
+// tunnelStopCmd represents the tunnel stop command
+var tunnelStopCmd = &cobra.Command{
+	Use:   "stop [instance-id...]",
+	Short: "Stop background tunnels.",
+	Long:  `Stop the background tunnels to the given instances, or every background tunnel with --all.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		if all == (len(args) > 0) {
+			return usageErrorf("give the instance IDs whose tunnels to stop, or --all")
+		}
+
+		tunnels, err := loadTunnels()
+		if err != nil {
+			return err
+		}
+
+		stopped := 0
+		for _, tunnel := range tunnels {
+			if !all && !containsString(args, tunnel.InstanceID) {
+				continue
+			}
+			if err := stopTunnel(tunnel); err != nil {
+				return fmt.Errorf("failed to stop tunnel to %s (pid %d): %v", tunnel.InstanceID, tunnel.PID, err)
+			}
+			fmt.Printf("Stopped tunnel to %s (pid %d)\n", tunnel.InstanceID, tunnel.PID)
+			stopped++
+		}
+		if stopped == 0 && !all {
+			return &NotFoundError{Kind: "tunnel for instance", ID: strings.Join(args, ", ")}
+		}
+		return nil
+	},
+}
+
+// portForward is a local port forwarded to a port on an instance
+type portForward struct {
+	RemotePort int `json:"remotePort"`
+	LocalPort  int `json:"localPort"`
+}
+
+// parsePortForwards parses <remote-port>[:<local-port>] arguments
+func parsePortForwards(args []string) ([]portForward, error) {
+	var forwards []portForward
+	for _, arg := range args {
+		remote, local, hasLocal := strings.Cut(arg, ":")
+		forward := portForward{}
+		var err error
+		if forward.RemotePort, err = parsePort(remote); err != nil {
+			return nil, usageErrorf("invalid port forward '%s': %v", arg, err)
+		}
+		forward.LocalPort = forward.RemotePort
+		if hasLocal {
+			if forward.LocalPort, err = parsePort(local); err != nil {
+				return nil, usageErrorf("invalid port forward '%s': %v", arg, err)
+			}
+		}
+		forwards = append(forwards, forward)
+	}
+	return forwards, nil
+}
+
+// parsePort parses a TCP port number
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("'%s' is not a port number (1-65535)", value)
+	}
+	return port, nil
+}
+
+// tunnelSSHArgs returns the arguments for ssh to hold the forwards open
+// without running a command, with batchOptions from batchSSHArgs
+func tunnelSSHArgs(endpoint hyperbolic.SSHEndpoint, identity string, batchOptions []string, forwards []portForward) []string {
+	args := append([]string{"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", fmt.Sprintf("ServerAliveInterval=%d", int(tunnelKeepAlive.Seconds())),
+		"-o", "ServerAliveCountMax=3",
+	}, batchOptions...)
+	for _, forward := range forwards {
+		args = append(args, "-L", fmt.Sprintf("127.0.0.1:%d:localhost:%d", forward.LocalPort, forward.RemotePort))
+	}
+	return append(args, sshArgs(endpoint, identity, nil)...)
+}
+
+// runTunnel runs ssh with args until interrupted, starting it again with a
+// growing delay whenever the connection drops. It gives up after
+// tunnelMaxFailures quick failures in a row.
+func runTunnel(ctx context.Context, sshPath string, args []string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	backoff := time.Second
+	failures := 0
+	for {
+		started := time.Now()
+		ssh := exec.CommandContext(ctx, sshPath, args...)
+		ssh.Stdout = os.Stdout
+		ssh.Stderr = os.Stderr
+		err := ssh.Run()
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if time.Since(started) >= tunnelStableAfter {
+			backoff, failures = time.Second, 0
+		}
+		failures++
+		if failures >= tunnelMaxFailures {
+			return fmt.Errorf("tunnel failed %d times in a row, giving up: %v", failures, err)
+		}
+
+		fmt.Fprintf(os.Stderr, "%s connection lost (%v); reconnecting in %s\n", time.Now().Format("15:04:05"), err, backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, tunnelMaxBackoff)
+	}
+}
+
+// tunnelState describes a background tunnel. It is saved by the process
+// that starts the tunnel and removed when the tunnel is stopped.
+type tunnelState struct {
+	PID        int           `json:"pid"`
+	InstanceID string        `json:"instanceId"`
+	Forwards   []portForward `json:"forwards"`
+	LogFile    string        `json:"logFile"`
+	StartedAt  time.Time     `json:"startedAt"`
+
+	// path is the file the state was loaded from
+	path string
+}
+
+// getTunnelDir returns the directory holding background tunnel state and logs
+func getTunnelDir() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "tunnels"), nil
+}
+
+// startBackgroundTunnel runs this command again as a detached process that
+// holds the tunnel open, with its output going to a log file
+func startBackgroundTunnel(cmd *cobra.Command, instanceID string, endpoint hyperbolic.SSHEndpoint, forwards []portForward) error {
+	dir, err := getTunnelDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create tunnel directory: %v", err)
+	}
+
+	// A local port can only be forwarded once, so it names the tunnel
+	name := fmt.Sprintf("%s-%d", sshHostAlias(hyperbolic.Instance{ID: instanceID}, 0), forwards[0].LocalPort)
+	logPath := filepath.Join(dir, name+".log")
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create tunnel log: %v", err)
+	}
+	defer logFile.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the hyperbolic executable: %v", err)
+	}
+
+	// The child is given the instance's endpoint so it does not need to call
+	// the API again, and runs the tunnel in the foreground
+	args := []string{"tunnel", instanceID}
+	for _, forward := range forwards {
+		args = append(args, fmt.Sprintf("%d:%d", forward.RemotePort, forward.LocalPort))
+	}
+	args = append(args, "--ssh-endpoint", endpoint.Command)
+	args = append(args, tunnelChildFlags(cmd)...)
+
+	child := exec.Command(executable, args...)
+	child.Stdout = logFile
+	child.Stderr = logFile
+	child.SysProcAttr = detachedProcAttr()
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to start background tunnel: %v", err)
+	}
+
+	state := tunnelState{
+		PID:        child.Process.Pid,
+		InstanceID: instanceID,
+		Forwards:   forwards,
+		LogFile:    logPath,
+		StartedAt:  time.Now().UTC(),
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tunnel state: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), data, 0600); err != nil {
+		child.Process.Kill()
+		return fmt.Errorf("failed to save tunnel state: %v", err)
+	}
+	child.Process.Release()
+
+	for _, forward := range forwards {
+		fmt.Printf("Forwarding localhost:%d -> %s port %d in the background (pid %d)\n", forward.LocalPort, instanceID, forward.RemotePort, state.PID)
+	}
+	fmt.Printf("Log: %s\n", logPath)
+	fmt.Println("Run 'hyperbolic tunnel stop " + instanceID + "' to stop it.")
+	return nil
+}
+
+// tunnelChildFlags returns the flags of this invocation that a background
+// tunnel process needs to repeat
+func tunnelChildFlags(cmd *cobra.Command) []string {
+	var flags []string
+	for _, name := range []string{"identity", "accept-new-host-keys", "api-url", "profile"} {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			flags = append(flags, "--"+name+"="+flag.Value.String())
+		}
+	}
+	return flags
+}
+
+// isTunnelProcess reports whether the background tunnel's process is still
+// running. Process IDs are reused once a process exits, so the process with
+// the saved ID only counts if its command line is the tunnel's own, as
+// started by startBackgroundTunnel.
+func isTunnelProcess(tunnel tunnelState) bool {
+	commandLine, ok := processCommandLine(tunnel.PID)
+	if !ok {
+		return false
+	}
+	return strings.Contains(commandLine, " tunnel "+tunnel.InstanceID+" ") && strings.Contains(commandLine, " --ssh-endpoint ")
+}
+
+// stopTunnel stops a background tunnel's process, after checking that the
+// process is still the tunnel. Its state is removed either way.
+func stopTunnel(tunnel tunnelState) error {
+	if isTunnelProcess(tunnel) {
+		if err := stopProcess(tunnel.PID); err != nil {
+			return err
+		}
+	}
+	removeTunnelState(tunnel)
+	return nil
+}
+
+// loadTunnels returns the background tunnels that are still running,
+// removing the state of any that have exited or whose process ID now belongs
+// to another process
+func loadTunnels() ([]tunnelState, error) {
+	dir, err := getTunnelDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var tunnels []tunnelState
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tunnel state: %v", err)
+		}
+		var tunnel tunnelState
+		if err := json.Unmarshal(data, &tunnel); err != nil {
+			return nil, fmt.Errorf("failed to parse tunnel state %s: %v", path, err)
+		}
+		tunnel.path = path
+		if !isTunnelProcess(tunnel) {
+			removeTunnelState(tunnel)
+			continue
+		}
+		tunnels = append(tunnels, tunnel)
+	}
+	sort.Slice(tunnels, func(i, j int) bool {
+		return tunnels[i].StartedAt.Before(tunnels[j].StartedAt)
+	})
+	return tunnels, nil
+}
+
+// removeTunnelState deletes the state file of a tunnel; its log is kept
+func removeTunnelState(tunnel tunnelState) {
+	if err := os.Remove(tunnel.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove %s: %v\n", tunnel.path, err)
+	}
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(tunnelCmd)
+	tunnelCmd.AddCommand(tunnelListCmd)
+	tunnelCmd.AddCommand(tunnelStopCmd)
+
+	tunnelCmd.Flags().Int("node", 1, "Node to connect to, for multi-node bare-metal rentals")
+	tunnelCmd.Flags().StringP("identity", "i", "", "Private key file to authenticate with (passed to ssh -i)")
+	tunnelCmd.Flags().BoolP("background", "d", false, "Run the tunnel in the background")
+	addHostKeyFlag(tunnelCmd)
+	tunnelCmd.Flags().String("ssh-endpoint", "", "SSH command of the node, used by background tunnels")
+	tunnelCmd.Flags().MarkHidden("ssh-endpoint")
+	tunnelStopCmd.Flags().Bool("all", false, "Stop every background tunnel")
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestLoadTunnelsChecksProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	dir, err := getTunnelDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	// start runs a shell with the given arguments after its script, which
+	// show up in its command line. It waits on a pipe, without starting
+	// another process that killing the shell would leave behind.
+	start := func(args ...string) int {
+		stdin, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		process := exec.Command("sh", append([]string{"-c", "read line"}, args...)...)
+		process.Stdin = stdin
+		if err := process.Start(); err != nil {
+			t.Fatal(err)
+		}
+		stdin.Close()
+		t.Cleanup(func() {
+			process.Process.Kill()
+			process.Wait()
+			writer.Close()
+		})
+		return process.Process.Pid
+	}
+	save := func(instanceID string, pid int) string {
+		data, err := json.Marshal(tunnelState{PID: pid, InstanceID: instanceID, StartedAt: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, instanceID+".json")
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// A process that has exited
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	running := save("1234", start("hyperbolic", "tunnel", "1234", "8888:8888", "--ssh-endpoint", "ssh ubuntu@host"))
+	stale := save("5678", exited.Process.Pid)
+	reused := save("9012", start("hyperbolic", "instances", "--watch"))
+	otherInstance := save("3456", start("hyperbolic", "tunnel", "1234", "8888:8888", "--ssh-endpoint", "ssh ubuntu@host"))
+
+	tunnels, err := loadTunnels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tunnels) != 1 || tunnels[0].InstanceID != "1234" {
+		t.Fatalf("got tunnels %+v, want only the tunnel to 1234", tunnels)
+	}
+	if _, err := os.Stat(running); err != nil {
+		t.Errorf("state of the running tunnel was removed: %v", err)
+	}
+	for _, path := range []string{stale, reused, otherInstance} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("stale state %s was kept", filepath.Base(path))
+		}
+	}
+
+	// A process that is not the tunnel is never signalled
+	pid := start("hyperbolic", "instances")
+	if err := stopTunnel(tunnelState{PID: pid, InstanceID: "7890", path: save("7890", pid)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := processCommandLine(pid); !ok {
+		t.Error("stopTunnel signalled a process that was not the tunnel")
+	}
+	if err := stopTunnel(tunnels[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(running); !os.IsNotExist(err) {
+		t.Error("state of the stopped tunnel was kept")
+	}
+}
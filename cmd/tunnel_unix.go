@@ -0,0 +1,41 @@
+//go:build !windows
+
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// detachedProcAttr starts a background tunnel in its own session, so it
+// keeps running after the terminal that started it is closed
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// processCommandLine returns the command line of the process with the given
+// ID, or false if no such process is running
+func processCommandLine(pid int) (string, bool) {
+	// -ww stops ps cutting the command line off at the terminal's width
+	output, err := exec.Command("ps", "-ww", "-o", "command=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", false
+	}
+	commandLine := strings.TrimSpace(string(output))
+	return commandLine, commandLine != ""
+}
+
+// stopProcess asks the process with the given ID to exit
+func stopProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Signal(syscall.SIGTERM)
+}
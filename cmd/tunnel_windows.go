@@ -0,0 +1,39 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// detachedProcAttr starts a background tunnel in its own process group, so
+// Ctrl-C in the console that started it does not stop it
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// processCommandLine returns the command line of the process with the given
+// ID, or false if no such process is running
+func processCommandLine(pid int) (string, bool) {
+	query := fmt.Sprintf("(Get-CimInstance Win32_Process -Filter 'ProcessId=%d').CommandLine", pid)
+	output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", query).Output()
+	if err != nil {
+		return "", false
+	}
+	commandLine := strings.TrimSpace(string(output))
+	return commandLine, commandLine != ""
+}
+
+// stopProcess ends the process with the given ID
+func stopProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Kill()
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/spf13/cobra"
+)
+
+// defaultWaitTimeout is how long --wait waits for a new rental by default
+const defaultWaitTimeout = 15 * time.Minute
+
+// waitOptions are the --wait flags of the rent commands
+type waitOptions struct {
+	enabled            bool
+	timeout            time.Duration
+	checkSSH           bool
+	terminateOnTimeout bool
+}
+
+// addWaitFlags adds --wait and its options to a command that creates a rental
+func addWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Wait until the instance is running and reachable over SSH, then print the SSH command")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "How long --wait waits before giving up")
+	cmd.Flags().Bool("wait-ssh", true, "With --wait, also wait until the SSH port accepts connections")
+	cmd.Flags().Bool("terminate-on-timeout", false, "With --wait, terminate the instance if it is not ready before --wait-timeout")
+}
+
+// waitOptionsFromFlags reads the --wait flags, so they can be checked before
+// anything is rented
+func waitOptionsFromFlags(cmd *cobra.Command) (waitOptions, error) {
+	var options waitOptions
+	options.enabled, _ = cmd.Flags().GetBool("wait")
+	options.timeout, _ = cmd.Flags().GetDuration("wait-timeout")
+	options.checkSSH, _ = cmd.Flags().GetBool("wait-ssh")
+	options.terminateOnTimeout, _ = cmd.Flags().GetBool("terminate-on-timeout")
+
+	if !options.enabled {
+		for _, name := range []string{"wait-timeout", "wait-ssh", "terminate-on-timeout"} {
+			if cmd.Flags().Changed(name) {
+				return options, usageErrorf("--%s can only be used with --wait", name)
+			}
+		}
+		return options, nil
+	}
+	if options.timeout <= 0 {
+		return options, usageErrorf("--wait-timeout must be positive")
+	}
+	return options, nil
+}
+
+// waitForRental waits until the new rental with the given ID is running and,
+// if requested, accepts SSH connections, printing progress along the way.
+// When the timeout passes first it returns a TimeoutError, after terminating
+// the rental if --terminate-on-timeout was given.
+func waitForRental(ctx context.Context, client *hyperbolic.Client, id string, options waitOptions) error {
+	start := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, options.timeout)
+	defer cancel()
+
+	fmt.Println()
+	fmt.Printf("Waiting up to %s for instance %s to start...\n", options.timeout, id)
+
+	lastStatus := ""
+	instance, err := client.WaitForInstance(waitCtx, id, hyperbolic.DefaultPollInterval, func(instance hyperbolic.Instance) {
+		if instance.Status != lastStatus {
+			fmt.Printf("  [%s] %s\n", formatElapsed(time.Since(start)), instance.Status)
+			lastStatus = instance.Status
+		}
+	})
+	if err == nil && options.checkSSH {
+		for _, endpoint := range instance.SSHEndpoints() {
+			fmt.Printf("  [%s] waiting for SSH on %s:%d\n", formatElapsed(time.Since(start)), endpoint.Host, endpoint.Port)
+			if err = hyperbolic.WaitForSSH(waitCtx, endpoint, hyperbolic.DefaultPollInterval); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			return err
+		}
+		err = &TimeoutError{Err: fmt.Errorf("instance %s was not ready after %s", id, options.timeout)}
+		if options.terminateOnTimeout {
+			return terminateAfterTimeout(ctx, client, id, instance, err)
+		}
+		return fmt.Errorf("%w\nThe instance is still rented. To terminate it, run:\n  hyperbolic terminate %s", err, id)
+	}
+
+	fmt.Printf("  [%s] ready\n", formatElapsed(time.Since(start)))
+	fmt.Println()
+	endpoints := instance.SSHEndpoints()
+	if len(endpoints) == 0 {
+		fmt.Printf("Instance %s is running. For connection details, run:\n", id)
+		fmt.Printf("  hyperbolic instances %s\n", id)
+		return nil
+	}
+	fmt.Printf("Instance %s is ready. Connect with:\n", id)
+	for _, endpoint := range endpoints {
+		fmt.Printf("  %s\n", endpoint.Command)
+	}
+	return nil
+}
+
+// terminateAfterTimeout terminates a rental that did not become ready in
+// time, and returns timeoutErr with the outcome added
+func terminateAfterTimeout(ctx context.Context, client *hyperbolic.Client, id string, instance hyperbolic.Instance, timeoutErr error) error {
+	// The rental may not have been listed before the timeout
+	if instance.ID == "" {
+		var err error
+		if instance, err = client.GetInstance(ctx, id); err != nil {
+			return fmt.Errorf("%w; could not terminate it: %v", timeoutErr, err)
+		}
+	}
+	if err := client.TerminateInstance(ctx, instance); err != nil {
+		return fmt.Errorf("%w; could not terminate it: %v", timeoutErr, err)
+	}
+	return fmt.Errorf("%w; terminated it", timeoutErr)
+}
+
+// formatElapsed formats the time since a wait started, e.g. 1m05s
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
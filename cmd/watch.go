@@ -0,0 +1,180 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// defaultWatchInterval is used when --watch is given without a value
+const defaultWatchInterval = 5 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\x1b[H\x1b[2J"
+
+// addWatchFlag adds --watch to a command whose output can be refreshed
+func addWatchFlag(cmd *cobra.Command) {
+	cmd.Flags().Duration("watch", 0, "Refresh the table every interval until Ctrl-C, highlighting changes (e.g. --watch or --watch=10s)")
+	cmd.Flags().Lookup("watch").NoOptDefVal = defaultWatchInterval.String()
+}
+
+// watchArgs wraps a command's argument check to reject an interval given
+// as "--watch 30s". The interval is optional, so it must be attached with
+// "=", and "30s" would otherwise be read as an argument.
+func watchArgs(check cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("watch") {
+			for _, arg := range args {
+				// "0" parses as a duration but may be an instance ID
+				if _, err := time.ParseDuration(arg); err == nil && arg != "0" {
+					return usageErrorf("'%s' looks like a --watch interval; give it as --watch=%s", arg, arg)
+				}
+			}
+		}
+		return check(cmd, args)
+	}
+}
+
+// watchInterval returns the interval chosen with --watch, or 0 if the
+// command should run once
+func watchInterval(cmd *cobra.Command, format printer.Format) (time.Duration, error) {
+	interval, _ := cmd.Flags().GetDuration("watch")
+	if !cmd.Flags().Changed("watch") {
+		return 0, nil
+	}
+	if interval <= 0 {
+		return 0, usageErrorf("--watch interval must be positive")
+	}
+	if !format.IsTable() {
+		return 0, usageErrorf("--watch can only be used with table output, not -o %s", format.Name)
+	}
+	return interval, nil
+}
+
+// tableHistory remembers the tables drawn in the previous frame of a watch,
+// so the next frame can highlight what changed
+type tableHistory struct {
+	previous map[string]*printer.Table
+	current  map[string]*printer.Table
+	// previousText and currentText are the same for text drawn with
+	// highlightText
+	previousText map[string]string
+	currentText  map[string]string
+	// colors is set when changes can be shown with ANSI colors
+	colors bool
+}
+
+// highlight marks the changes in table since the previous frame's table of
+// the same name. A nil history does nothing, for commands run once.
+func (h *tableHistory) highlight(name string, table *printer.Table) {
+	if h == nil {
+		return
+	}
+	if h.colors {
+		table.Highlight(h.previous[name])
+	}
+	h.current[name] = table
+}
+
+// highlightText is highlight for text made of "Label: value" lines, such as
+// the details of one instance. It returns text with the lines that changed
+// since the previous frame highlighted; lines with a label in volatile,
+// which change on their own, never are.
+func (h *tableHistory) highlightText(name, text string, volatile ...string) string {
+	if h == nil {
+		return text
+	}
+	h.currentText[name] = text
+	previous, ok := h.previousText[name]
+	if !h.colors || !ok {
+		return text
+	}
+	return printer.HighlightLines(text, previous, volatile...)
+}
+
+// watch calls render every interval and redraws the screen with its output
+// until interrupted with Ctrl-C, which ends the command successfully. If a
+// refresh fails the error is shown and the previous tables are kept for
+// comparison; only a failure of the first refresh ends the watch.
+func watch(ctx context.Context, interval time.Duration, command string, render func(ctx context.Context, w io.Writer, history *tableHistory) error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Redraw in place on a terminal; otherwise separate frames with a blank
+	// line so the output can be logged
+	interactive := term.IsTerminal(int(os.Stdout.Fd()))
+	history := &tableHistory{previous: map[string]*printer.Table{}, previousText: map[string]string{}, colors: interactive}
+
+	for frame := 0; ; frame++ {
+		history.current, history.currentText = map[string]*printer.Table{}, map[string]string{}
+
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "Every %s: %s    %s\n\n", interval, command, time.Now().Format("2006-01-02 15:04:05"))
+		err := render(ctx, &buf, history)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if frame == 0 {
+				return err
+			}
+			fmt.Fprintf(&buf, "Error: %v\n", err)
+		} else {
+			history.previous, history.previousText = history.current, history.currentText
+		}
+
+		if interactive {
+			fmt.Print(clearScreen)
+		} else if frame > 0 {
+			fmt.Println()
+		}
+		fmt.Print(buf.String())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// secretFlags are flags whose values are hidden when a command line is shown
+var secretFlags = []string{"api-key"}
+
+// watchCommandLine returns the command line shown at the top of each frame
+func watchCommandLine() string {
+	return "hyperbolic " + strings.Join(redactArgs(os.Args[1:]), " ")
+}
+
+// redactArgs returns a copy of args with the values of secretFlags replaced,
+// so they do not end up on screen or in recordings of it
+func redactArgs(args []string) []string {
+	redacted := append([]string{}, args...)
+	for i := 0; i < len(redacted); i++ {
+		if redacted[i] == "--" {
+			break
+		}
+		for _, name := range secretFlags {
+			if strings.HasPrefix(redacted[i], "--"+name+"=") {
+				redacted[i] = "--" + name + "=***"
+			} else if redacted[i] == "--"+name && i+1 < len(redacted) {
+				i++
+				redacted[i] = "***"
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/internal/printer"
+)
+
+func TestWatchArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"spot", "--watch", "30s"}, "give it as --watch=30s"},
+		{[]string{"spot", "30s", "--watch"}, "give it as --watch=30s"},
+		{[]string{"instances", "--watch", "1m30s"}, "give it as --watch=1m30s"},
+		{[]string{"spot", "extra"}, "unknown command"},
+		{[]string{"ondemand", "extra"}, "unknown command"},
+		{[]string{"account", "extra"}, "unknown command"},
+		{[]string{"rent", "spot", "extra"}, "unknown command"},
+		{[]string{"instances", "1002", "extra"}, "accepts at most 1 arg"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			resetFlags(rootCmd)
+			rootCmd.SetArgs(tt.args)
+			rootCmd.SetErr(io.Discard)
+			t.Cleanup(func() {
+				resetFlags(rootCmd)
+				rootCmd.SetErr(nil)
+			})
+
+			err := rootCmd.ExecuteContext(context.Background())
+			if exitCode(err) != ExitUsage {
+				t.Fatalf("expected a usage error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("error %q does not contain %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestHighlightText(t *testing.T) {
+	history := &tableHistory{previousText: map[string]string{}, colors: true}
+	frame := func(text string) string {
+		history.currentText = map[string]string{}
+		text = history.highlightText("instance", text, "Uptime")
+		history.previousText = history.currentText
+		return text
+	}
+
+	first := "Status: starting\nUptime: 1m\nPrice: $1.00/hr\n"
+	if got := frame(first); got != first {
+		t.Errorf("first frame was highlighted: %q", got)
+	}
+	got := frame("Status: running\nUptime: 2m\nPrice: $1.00/hr\nSSH Command: ssh ubuntu@host\n")
+	want := "\x1b[1;33mStatus: running\x1b[0m\nUptime: 2m\nPrice: $1.00/hr\n\x1b[1;32mSSH Command: ssh ubuntu@host\x1b[0m\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Commands run once leave the text alone
+	if got := (*tableHistory)(nil).highlightText("instance", first); got != first {
+		t.Errorf("nil history changed the text: %q", got)
+	}
+	if got := printer.HighlightLines(first, first); got != first {
+		t.Errorf("unchanged text was highlighted: %q", got)
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"spot", "--watch=10s"}, "spot --watch=10s"},
+		{[]string{"instances", "--api-key", "secret", "--watch"}, "instances --api-key *** --watch"},
+		{[]string{"instances", "--api-key=secret", "--watch"}, "instances --api-key=*** --watch"},
+		{[]string{"spot", "--watch", "--api-key"}, "spot --watch --api-key"},
+		{[]string{"exec", "1234", "--", "echo", "--api-key", "shown"}, "exec 1234 -- echo --api-key shown"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(redactArgs(tt.args), " "); got != tt.want {
+			t.Errorf("redactArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	// os.Args is left alone
+	args := []string{"spot", "--api-key", "secret"}
+	redactArgs(args)
+	if args[2] != "secret" {
+		t.Errorf("redactArgs changed its argument to %q", args)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256
+const pbkdf2Iterations = 600000
+
+// ErrWrongPassphrase is returned when the credentials file cannot be
+// decrypted with the given passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted credentials file")
+
+// EncryptedFileStore keeps API keys in a file encrypted with AES-256-GCM,
+// using a key derived from a passphrase with PBKDF2.
+type EncryptedFileStore struct {
+	// Path is the credentials file, usually ~/.hyperbolic/credentials.enc
+	Path string
+	// Passphrase is called when the file needs to be decrypted or encrypted
+	Passphrase func() (string, error)
+}
+
+// encryptedFile is the on-disk format of the credentials file
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (s *EncryptedFileStore) Name() string {
+	return StoreEncrypted
+}
+
+func (s *EncryptedFileStore) Get(profile string) (string, error) {
+	keys, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	apiKey, ok := keys[profile]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return apiKey, nil
+}
+
+func (s *EncryptedFileStore) Set(profile, apiKey string) error {
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	keys[profile] = apiKey
+	return s.save(keys)
+}
+
+func (s *EncryptedFileStore) Delete(profile string) error {
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := keys[profile]; !ok {
+		return ErrNotFound
+	}
+	delete(keys, profile)
+	return s.save(keys)
+}
+
+// load decrypts the credentials file. A missing file is an empty store.
+func (s *EncryptedFileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %v", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %v", err)
+	}
+	if file.Version != 1 {
+		return nil, fmt.Errorf("unsupported credentials file version %d", file.Version)
+	}
+
+	gcm, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	keys := map[string]string{}
+	if err := json.Unmarshal(plaintext, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted credentials: %v", err)
+	}
+	return keys, nil
+}
+
+// save encrypts keys with a fresh salt and nonce and writes the credentials file
+func (s *EncryptedFileStore) save(keys map[string]string) error {
+	plaintext, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %v", err)
+	}
+
+	file := encryptedFile{Version: 1, Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %v", err)
+	}
+	gcm, err := s.cipher(file.Salt)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %v", err)
+	}
+	if err := os.WriteFile(s.Path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %v", err)
+	}
+	return nil
+}
+
+// cipher derives the AES-GCM cipher for salt from the passphrase
+func (s *EncryptedFileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	if s.Passphrase == nil {
+		return nil, errors.New("no passphrase available for the encrypted credentials file")
+	}
+	passphrase, err := s.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, errors.New("passphrase cannot be empty")
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringService is the service name API keys are stored under in the OS keyring
+const KeyringService = "hyperbolic-cli"
+
+// Keyring is the subset of an OS secret store used by KeyringStore.
+// Implementations must return ErrNotFound for missing entries.
+type Keyring interface {
+	Get(service, user string) (string, error)
+	Set(service, user, password string) error
+	Delete(service, user string) error
+}
+
+// KeyringStore keeps API keys in a Keyring, one entry per profile
+type KeyringStore struct {
+	Keyring Keyring
+}
+
+// NewKeyringStore returns a store backed by the operating system's keyring
+func NewKeyringStore() *KeyringStore {
+	return &KeyringStore{Keyring: systemKeyring{}}
+}
+
+func (s *KeyringStore) Name() string {
+	return StoreKeyring
+}
+
+func (s *KeyringStore) Get(profile string) (string, error) {
+	apiKey, err := s.Keyring.Get(KeyringService, profile)
+	if err != nil {
+		return "", wrapKeyringError(err)
+	}
+	return apiKey, nil
+}
+
+func (s *KeyringStore) Set(profile, apiKey string) error {
+	return wrapKeyringError(s.Keyring.Set(KeyringService, profile, apiKey))
+}
+
+func (s *KeyringStore) Delete(profile string) error {
+	return wrapKeyringError(s.Keyring.Delete(KeyringService, profile))
+}
+
+func wrapKeyringError(err error) error {
+	if err == nil || errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return fmt.Errorf("keyring unavailable: %w", err)
+}
+
+// systemKeyring adapts go-keyring to the Keyring interface
+type systemKeyring struct{}
+
+func (systemKeyring) Get(service, user string) (string, error) {
+	secret, err := keyring.Get(service, user)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	return secret, err
+}
+
+func (systemKeyring) Set(service, user, password string) error {
+	return keyring.Set(service, user, password)
+}
+
+func (systemKeyring) Delete(service, user string) error {
+	err := keyring.Delete(service, user)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// MemoryKeyring is an in-memory Keyring for tests and systems without a
+// secret service. Its zero value is ready to use.
+type MemoryKeyring struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func (k *MemoryKeyring) Get(service, user string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	secret, ok := k.secrets[service+"/"+user]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (k *MemoryKeyring) Set(service, user, password string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.secrets == nil {
+		k.secrets = map[string]string{}
+	}
+	k.secrets[service+"/"+user] = password
+	return nil
+}
+
+func (k *MemoryKeyring) Delete(service, user string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.secrets[service+"/"+user]; !ok {
+		return ErrNotFound
+	}
+	delete(k.secrets, service+"/"+user)
+	return nil
+}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+
+// Package credentials stores Hyperbolic API keys outside the plaintext
+// config file.
+//
+// Each Store keeps one API key per profile name. KeyringStore uses the
+// operating system's secret store (Secret Service over D-Bus on Linux,
+// Keychain on macOS, Credential Manager on Windows). EncryptedFileStore keeps
+// keys in a passphrase-protected file for systems without a keyring.
+package credentials
+
+import (
+	"errors"
+)
+
+// Names of the supported credential stores, as accepted by
+// 'hyperbolic auth --store'
+const (
+	StoreKeyring   = "keyring"
+	StoreEncrypted = "encrypted"
+	StoreFile      = "file"
+)
+
+// ErrNotFound is returned by Store.Get and Store.Delete when no API key is
+// stored for the profile
+var ErrNotFound = errors.New("no API key stored for profile")
+
+// Store persists API keys by profile name
+type Store interface {
+	// Name returns the store's name, e.g. "keyring"
+	Name() string
+	// Get returns the API key stored for profile
+	Get(profile string) (string, error)
+	// Set stores apiKey for profile, replacing any existing key
+	Set(profile, apiKey string) error
+	// Delete removes the API key stored for profile
+	Delete(profile string) error
+}
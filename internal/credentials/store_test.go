@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func passphrase(value string) func() (string, error) {
+	return func() (string, error) {
+		return value, nil
+	}
+}
+
+// testRoundTrip stores, reads, replaces and deletes keys for two profiles
+func testRoundTrip(t *testing.T, store Store) {
+	t.Helper()
+
+	if _, err := store.Get("default"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get on an empty store: expected ErrNotFound, got %v", err)
+	}
+	if err := store.Set("default", "key-1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set("work", "key-2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set("default", "key-3"); err != nil {
+		t.Fatalf("Set replacing a key: %v", err)
+	}
+
+	for profile, want := range map[string]string{"default": "key-3", "work": "key-2"} {
+		got, err := store.Get(profile)
+		if err != nil || got != want {
+			t.Errorf("Get(%q) = %q, %v; want %q", profile, got, err, want)
+		}
+	}
+
+	if err := store.Delete("default"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get("default"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: expected ErrNotFound, got %v", err)
+	}
+	if err := store.Delete("default"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete of a missing key: expected ErrNotFound, got %v", err)
+	}
+	if got, err := store.Get("work"); err != nil || got != "key-2" {
+		t.Errorf("other profile after Delete: got %q, %v", got, err)
+	}
+}
+
+func TestKeyringStoreRoundTrip(t *testing.T) {
+	testRoundTrip(t, &KeyringStore{Keyring: &MemoryKeyring{}})
+}
+
+func TestEncryptedFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	testRoundTrip(t, &EncryptedFileStore{Path: path, Passphrase: passphrase("correct horse")})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "key-2") {
+		t.Error("credentials file contains an API key in plaintext")
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0077 != 0 {
+		t.Errorf("credentials file is readable by others: %v", info.Mode().Perm())
+	}
+}
+
+func TestEncryptedFileStoreWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	store := &EncryptedFileStore{Path: path, Passphrase: passphrase("correct horse")}
+	if err := store.Set("default", "key-1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	wrong := &EncryptedFileStore{Path: path, Passphrase: passphrase("battery staple")}
+	if _, err := wrong.Get("default"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Get: expected ErrWrongPassphrase, got %v", err)
+	}
+	// A wrong passphrase must not overwrite the file with a new one
+	if err := wrong.Set("work", "key-2"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Set: expected ErrWrongPassphrase, got %v", err)
+	}
+	if got, err := store.Get("default"); err != nil || got != "key-1" {
+		t.Errorf("Get with the right passphrase: got %q, %v", got, err)
+	}
+}
+
+func TestEncryptedFileStoreNoPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+
+	empty := &EncryptedFileStore{Path: path, Passphrase: passphrase("")}
+	if err := empty.Set("default", "key-1"); err == nil {
+		t.Error("expected an empty passphrase to be rejected")
+	}
+	missing := &EncryptedFileStore{Path: path}
+	if err := missing.Set("default", "key-1"); err == nil {
+		t.Error("expected a store without a passphrase to fail")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no credentials file to be written, got %v", err)
+	}
+}
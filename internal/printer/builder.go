@@ -0,0 +1,134 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package printer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Field is a column of a TableBuilder, computed from one item of type T
+type Field[T any] struct {
+	// Name identifies the field in --columns, e.g. "gpu-model"
+	Name   string
+	Header string
+	Wide   bool
+	// Volatile fields change on their own, like an uptime, so Highlight
+	// ignores them
+	Volatile bool
+	Value    func(T) string
+}
+
+// SortKey is an order that items of type T can be listed in
+type SortKey[T any] struct {
+	// Name identifies the key in --sort-by, e.g. "price"
+	Name    string
+	Compare func(a, b T) int
+}
+
+// TableBuilder builds tables whose columns and row order can be chosen by
+// the user, so listing commands share the same --columns and --sort-by
+// behaviour
+type TableBuilder[T any] struct {
+	Fields   []Field[T]
+	SortKeys []SortKey[T]
+	// Key, if set, identifies an item across refreshes. It sets Table.Keys
+	// so changes can be highlighted.
+	Key func(T) string
+}
+
+// FieldNames returns the names of the fields, in order
+func (b TableBuilder[T]) FieldNames() []string {
+	names := make([]string, 0, len(b.Fields))
+	for _, field := range b.Fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// SortKeyNames returns the names of the sort keys, in order
+func (b TableBuilder[T]) SortKeyNames() []string {
+	names := make([]string, 0, len(b.SortKeys))
+	for _, key := range b.SortKeys {
+		names = append(names, key.Name)
+	}
+	return names
+}
+
+// Select returns a builder with only the named fields, in the given order.
+// Selected fields are always shown, even if they are wide.
+func (b TableBuilder[T]) Select(names []string) (TableBuilder[T], error) {
+	selected := TableBuilder[T]{SortKeys: b.SortKeys, Key: b.Key}
+	for _, name := range names {
+		field, ok := b.field(strings.TrimSpace(name))
+		if !ok {
+			return b, fmt.Errorf("unknown column '%s'. Must be one of: %s", name, strings.Join(b.FieldNames(), ", "))
+		}
+		field.Wide = false
+		selected.Fields = append(selected.Fields, field)
+	}
+	if len(selected.Fields) == 0 {
+		return b, fmt.Errorf("no columns selected. Choose from: %s", strings.Join(b.FieldNames(), ", "))
+	}
+	return selected, nil
+}
+
+func (b TableBuilder[T]) field(name string) (Field[T], bool) {
+	for _, field := range b.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Field[T]{}, false
+}
+
+// Sort orders items by the named key, descending if reverse is set. Items
+// that compare equal keep their existing order. With an empty key the
+// existing order is kept, or reversed if reverse is set.
+func (b TableBuilder[T]) Sort(items []T, key string, reverse bool) error {
+	if key == "" {
+		if reverse {
+			for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+				items[i], items[j] = items[j], items[i]
+			}
+		}
+		return nil
+	}
+	for _, sortKey := range b.SortKeys {
+		if sortKey.Name != key {
+			continue
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			if reverse {
+				return sortKey.Compare(items[j], items[i]) < 0
+			}
+			return sortKey.Compare(items[i], items[j]) < 0
+		})
+		return nil
+	}
+	return fmt.Errorf("unknown sort key '%s'. Must be one of: %s", key, strings.Join(b.SortKeyNames(), ", "))
+}
+
+// Table returns the items as a table, one row per item
+func (b TableBuilder[T]) Table(items []T) *Table {
+	table := &Table{}
+	if b.Key != nil {
+		table.Keys = []string{}
+	}
+	for _, field := range b.Fields {
+		table.Columns = append(table.Columns, Column{Header: field.Header, Wide: field.Wide, Volatile: field.Volatile})
+	}
+	for _, item := range items {
+		values := make([]string, 0, len(b.Fields))
+		for _, field := range b.Fields {
+			values = append(values, field.Value(item))
+		}
+		table.Append(values...)
+		if b.Key != nil {
+			table.Keys = append(table.Keys, b.Key(item))
+		}
+	}
+	return table
+}
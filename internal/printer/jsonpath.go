@@ -0,0 +1,521 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a compiled kubectl-style JSONPath template such as
+// '{range .instances[*]}{.id}{"\t"}{.status}{"\n"}{end}'.
+//
+// Text outside braces is printed as is. Inside braces the supported syntax
+// is: '.field' or "['field']", '[n]' (negative counts from the end), slices
+// such as '[1:3]' or '[::2]', '[*]' and '.*', '..field' to find a field at
+// any depth, filters such as '[?(@.status=="running")]' using == or != (or
+// '[?(@.field)]' to test that a field is set), '$' for the root, quoted
+// strings such as "\n", and '{range EXPR}...{end}' to repeat a template for
+// each result of an expression. Multiple results of one expression are
+// separated by spaces.
+type JSONPath struct {
+	nodes []jpNode
+}
+
+// jpNode is a piece of a template: literal text, an expression, or a range
+// over an expression
+type jpNode struct {
+	text  string
+	path  []jpStep
+	isRef bool
+	body  []jpNode
+	isRng bool
+}
+
+// jpStep is one step of a path expression
+type jpStep struct {
+	kind     jpStepKind
+	field    string
+	index    int
+	slice    [3]*int // start, end and step of a slice; nil when omitted
+	filter   []jpStep
+	filterOp string
+	operand  string
+}
+
+type jpStepKind int
+
+const (
+	stepRoot jpStepKind = iota
+	stepField
+	stepIndex
+	stepWildcard
+	stepFilter
+	stepSlice
+	stepRecursive
+)
+
+// ParseJSONPath compiles a JSONPath template
+func ParseJSONPath(text string) (*JSONPath, error) {
+	nodes, rest, err := parseJSONPathNodes(text, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errors.New("unexpected {end}")
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// parseJSONPathNodes parses nodes up to the end of text, or up to a matching
+// {end} if inRange is set, and returns the text after it
+func parseJSONPathNodes(text string, inRange bool) ([]jpNode, string, error) {
+	var nodes []jpNode
+	for text != "" {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			nodes = append(nodes, jpNode{text: text})
+			text = ""
+			break
+		}
+		if start > 0 {
+			nodes = append(nodes, jpNode{text: text[:start]})
+		}
+		end := closingBrace(text, start)
+		if end < 0 {
+			return nil, "", fmt.Errorf("unclosed '{' in %q", text[start:])
+		}
+		expr := strings.TrimSpace(text[start+1 : end])
+		text = text[end+1:]
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, "", errors.New("{end} without {range}")
+			}
+			return nodes, text, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseJSONPathNodes(text, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jpNode{path: path, body: body, isRng: true})
+			text = rest
+		case strings.HasPrefix(expr, `"`):
+			literal, err := strconv.Unquote(expr)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid string literal %s", expr)
+			}
+			nodes = append(nodes, jpNode{text: literal})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jpNode{path: path, isRef: true})
+		}
+	}
+	if inRange {
+		return nil, "", errors.New("{range} without {end}")
+	}
+	return nodes, "", nil
+}
+
+// closingBrace returns the index of the '}' matching the '{' at start,
+// ignoring braces inside quoted strings
+func closingBrace(text string, start int) int {
+	var quote byte
+	for i := start + 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+// parsePath parses a path expression such as $.instances[0].id
+func parsePath(expr string) ([]jpStep, error) {
+	var steps []jpStep
+	rest := expr
+	switch {
+	case strings.HasPrefix(rest, "$"):
+		steps = append(steps, jpStep{kind: stepRoot})
+		rest = rest[1:]
+	case strings.HasPrefix(rest, "@"):
+		rest = rest[1:]
+	}
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, ".") {
+				// '..' selects the value and everything below it; the field,
+				// '*' or subscript that follows is applied to all of them
+				steps = append(steps, jpStep{kind: stepRecursive})
+				if len(rest) < 2 || !(rest[1] == '*' || rest[1] == '[' || isFieldChar(rest[1])) {
+					return nil, fmt.Errorf("invalid path %q: expected a field name after '..'", expr)
+				}
+				if rest[1] == '[' {
+					rest = rest[1:]
+				}
+				continue
+			}
+			if strings.HasPrefix(rest, "*") {
+				steps = append(steps, jpStep{kind: stepWildcard})
+				rest = rest[1:]
+				continue
+			}
+			n := 0
+			for n < len(rest) && isFieldChar(rest[n]) {
+				n++
+			}
+			if n == 0 {
+				// A lone "." refers to the current value
+				if rest == "" && len(steps) == 0 {
+					break
+				}
+				return nil, fmt.Errorf("invalid path %q: expected a field name after '.'", expr)
+			}
+			steps = append(steps, jpStep{kind: stepField, field: rest[:n]})
+			rest = rest[n:]
+		case '[':
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed '['", expr)
+			}
+			step, err := parseBracket(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %v", expr, err)
+			}
+			steps = append(steps, step)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", expr, rest[0])
+		}
+	}
+	return steps, nil
+}
+
+func isFieldChar(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// closingBracket returns the index of the ']' matching the '[' at the start
+// of text, ignoring brackets inside quoted strings
+func closingBracket(text string) int {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseBracket parses the contents of [...]
+func parseBracket(inner string) (jpStep, error) {
+	switch {
+	case inner == "*":
+		return jpStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, `"`):
+		field, err := unquote(inner)
+		if err != nil {
+			return jpStep{}, err
+		}
+		return jpStep{kind: stepField, field: field}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		return parseFilter(strings.TrimSpace(inner[2 : len(inner)-1]))
+	case strings.Contains(inner, ":"):
+		return parseSlice(inner)
+	}
+	index, err := strconv.Atoi(inner)
+	if err != nil {
+		return jpStep{}, fmt.Errorf("unsupported subscript [%s]", inner)
+	}
+	return jpStep{kind: stepIndex, index: index}, nil
+}
+
+// parseSlice parses a slice subscript such as 1:3, -2: or ::2
+func parseSlice(inner string) (jpStep, error) {
+	parts := strings.Split(inner, ":")
+	if len(parts) > 3 {
+		return jpStep{}, fmt.Errorf("unsupported subscript [%s]", inner)
+	}
+	step := jpStep{kind: stepSlice}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return jpStep{}, fmt.Errorf("unsupported subscript [%s]", inner)
+		}
+		step.slice[i] = &n
+	}
+	if step.slice[2] != nil && *step.slice[2] <= 0 {
+		return jpStep{}, fmt.Errorf("slice step must be positive in [%s]", inner)
+	}
+	return step, nil
+}
+
+// parseFilter parses a filter such as @.status=="running"
+func parseFilter(expr string) (jpStep, error) {
+	step := jpStep{kind: stepFilter}
+	left := expr
+	for _, op := range []string{"==", "!="} {
+		if i := strings.Index(expr, op); i >= 0 {
+			left = strings.TrimSpace(expr[:i])
+			step.filterOp = op
+			operand := strings.TrimSpace(expr[i+len(op):])
+			if strings.HasPrefix(operand, "'") || strings.HasPrefix(operand, `"`) {
+				unquoted, err := unquote(operand)
+				if err != nil {
+					return jpStep{}, err
+				}
+				operand = unquoted
+			}
+			step.operand = operand
+			break
+		}
+	}
+	if !strings.HasPrefix(left, "@") {
+		return jpStep{}, fmt.Errorf("filter %q must start with @", expr)
+	}
+	path, err := parsePath(left)
+	if err != nil {
+		return jpStep{}, err
+	}
+	step.filter = path
+	return step, nil
+}
+
+// unquote removes single or double quotes from a string literal
+func unquote(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string literal %s", s)
+	}
+	return unquoted, nil
+}
+
+// Execute evaluates the template against data, which must be the generic
+// JSON form of a value (maps, slices and scalars)
+func (j *JSONPath) Execute(w io.Writer, data interface{}) error {
+	return executeJSONPath(w, j.nodes, data, data)
+}
+
+func executeJSONPath(w io.Writer, nodes []jpNode, root, current interface{}) error {
+	for _, node := range nodes {
+		switch {
+		case node.isRng:
+			for _, value := range evalPath(node.path, root, current) {
+				if err := executeJSONPath(w, node.body, root, value); err != nil {
+					return err
+				}
+			}
+		case node.isRef:
+			values := evalPath(node.path, root, current)
+			texts := make([]string, 0, len(values))
+			for _, value := range values {
+				text, err := formatValue(value)
+				if err != nil {
+					return err
+				}
+				texts = append(texts, text)
+			}
+			if _, err := io.WriteString(w, strings.Join(texts, " ")); err != nil {
+				return err
+			}
+		default:
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// evalPath returns every value path selects. Missing fields and indexes out
+// of range select nothing rather than failing.
+func evalPath(path []jpStep, root, current interface{}) []interface{} {
+	values := []interface{}{current}
+	for _, step := range path {
+		var next []interface{}
+		for _, value := range values {
+			switch step.kind {
+			case stepRoot:
+				next = append(next, root)
+			case stepField:
+				if object, ok := value.(map[string]interface{}); ok {
+					if field, ok := object[step.field]; ok {
+						next = append(next, field)
+					}
+				}
+			case stepIndex:
+				if array, ok := value.([]interface{}); ok {
+					index := step.index
+					if index < 0 {
+						index += len(array)
+					}
+					if index >= 0 && index < len(array) {
+						next = append(next, array[index])
+					}
+				}
+			case stepSlice:
+				if array, ok := value.([]interface{}); ok {
+					next = append(next, sliceArray(array, step.slice)...)
+				}
+			case stepWildcard:
+				next = append(next, children(value)...)
+			case stepRecursive:
+				next = append(next, descendants(value)...)
+			case stepFilter:
+				for _, child := range children(value) {
+					if matchesFilter(step, root, child) {
+						next = append(next, child)
+					}
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// sliceArray returns the elements of array selected by a slice. As in
+// Python, negative bounds count from the end and bounds out of range are
+// clamped.
+func sliceArray(array []interface{}, slice [3]*int) []interface{} {
+	bound := func(value *int, fallback int) int {
+		if value == nil {
+			return fallback
+		}
+		n := *value
+		if n < 0 {
+			n += len(array)
+		}
+		return max(0, min(n, len(array)))
+	}
+	start, end, step := bound(slice[0], 0), bound(slice[1], len(array)), 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+
+	var values []interface{}
+	for i := start; i < end; i += step {
+		values = append(values, array[i])
+	}
+	return values
+}
+
+// descendants returns value followed by every value nested in it, depth
+// first, with object values in key order
+func descendants(value interface{}) []interface{} {
+	values := []interface{}{value}
+	for _, child := range children(value) {
+		values = append(values, descendants(child)...)
+	}
+	return values
+}
+
+// children returns the elements of an array, or the values of an object in
+// key order
+func children(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, v[key])
+		}
+		return values
+	}
+	return nil
+}
+
+func matchesFilter(step jpStep, root, value interface{}) bool {
+	results := evalPath(step.filter, root, value)
+	if step.filterOp == "" {
+		return len(results) > 0 && results[0] != nil
+	}
+	matched := false
+	for _, result := range results {
+		text, err := formatValue(result)
+		if err == nil && text == step.operand {
+			matched = true
+			break
+		}
+	}
+	if step.filterOp == "!=" {
+		return !matched
+	}
+	return matched
+}
+
+// formatValue prints scalars as plain text and objects and arrays as JSON
+func formatValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
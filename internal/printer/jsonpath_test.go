@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package printer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonPathDocument = `{
+  "kind": "InstanceList",
+  "items": [
+    {"id": "a", "status": "running", "gpus": 8, "spot": true, "ports": [22, 80], "node": {"region": "us-east"}},
+    {"id": "b", "status": "starting", "gpus": 1, "spot": false, "node": {"region": "eu-central"}},
+    {"id": "c", "status": "running", "gpus": 2.5, "owner": null, "node": {"region": "us-west", "labels": {"id": "nested"}}},
+    {"id": "d:e", "status": "terminated", "gpus": 4, "tags": {"key with space": "x"}}
+  ]
+}`
+
+func TestJSONPath(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(jsonPathDocument))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		// Fields and indexes
+		{"field", "{.kind}", "InstanceList"},
+		{"root", "{$.kind}", "InstanceList"},
+		{"bracket field", "{['kind']}", "InstanceList"},
+		{"double quoted bracket field", `{.items[3].tags["key with space"]}`, "x"},
+		{"nested field", "{.items[0].node.region}", "us-east"},
+		{"index", "{.items[1].id}", "b"},
+		{"negative index", "{.items[-1].id}", "d:e"},
+		{"index out of range", "{.items[9].id}", ""},
+		{"missing field", "{.items[0].missing}", ""},
+		{"text around expressions", "kind={.kind}!", "kind=InstanceList!"},
+		{"string literals", `{.items[0].id}{"\t"}{.items[1].id}{"\n"}`, "a\tb\n"},
+
+		// Wildcards
+		{"wildcard", "{.items[*].id}", "a b c d:e"},
+		{"dot wildcard", "{.items[0].node.*}", "us-east"},
+		{"array wildcard", "{.items[0].ports[*]}", "22 80"},
+		{"object wildcard in key order", "{.items[2].node.*}", `{"id":"nested"} us-west`},
+
+		// Slices
+		{"slice", "{.items[1:3].id}", "b c"},
+		{"slice from start", "{.items[:2].id}", "a b"},
+		{"slice to end", "{.items[2:].id}", "c d:e"},
+		{"slice with negative start", "{.items[-2:].id}", "c d:e"},
+		{"slice with negative end", "{.items[:-3].id}", "a"},
+		{"slice with step", "{.items[::2].id}", "a c"},
+		{"slice with start and step", "{.items[1::2].id}", "b d:e"},
+		{"slice clamped", "{.items[2:99].id}", "c d:e"},
+		{"empty slice", "{.items[3:1].id}", ""},
+
+		// Recursive descent
+		{"recursive field", "{..region}", "us-east eu-central us-west"},
+		{"recursive field includes nested matches", "{.items[2]..id}", "c nested"},
+		{"recursive index", "{.items[0]..[1]}", "80"},
+		{"recursive wildcard", "{.items[1].node..*}", "eu-central"},
+
+		// Filters
+		{"filter equal", `{.items[?(@.status=="running")].id}`, "a c"},
+		{"filter single quotes", `{.items[?(@.status=='starting')].id}`, "b"},
+		{"filter not equal", `{.items[?(@.status!="running")].id}`, "b d:e"},
+		{"filter on number", `{.items[?(@.gpus==8)].id}`, "a"},
+		{"filter on decimal", `{.items[?(@.gpus==2.5)].id}`, "c"},
+		{"filter on bool", `{.items[?(@.spot==false)].id}`, "b"},
+		{"filter on nested field", `{.items[?(@.node.region=="eu-central")].id}`, "b"},
+		{"filter existence", `{.items[?(@.ports)].id}`, "a"},
+		{"filter existence ignores null", `{.items[?(@.owner)].id}`, ""},
+		{"filter value containing a colon", `{.items[?(@.id=="d:e")].status}`, "terminated"},
+		{"filter with no match", `{.items[?(@.status=="gone")].id}`, ""},
+
+		// Ranges
+		{"range", `{range .items[*]}{.id}={.status}{"\n"}{end}`, "a=running\nb=starting\nc=running\nd:e=terminated\n"},
+		{"range over a filter", `{range .items[?(@.spot==true)]}{.id}{end}`, "a"},
+		{"range refers to the root", `{range .items[:2]}{$.kind}/{.id} {end}`, "InstanceList/a InstanceList/b "},
+		{"nested range", `{range .items[:1]}{range .ports[*]}[{.}]{end}{end}`, "[22][80]"},
+
+		// Objects and arrays are printed as JSON
+		{"object", "{.items[0].node}", `{"region":"us-east"}`},
+		{"array", "{.items[0].ports}", "[22,80]"},
+		{"null", "{.items[2].owner}", ""},
+		{"braces in a string literal", `{"{"}{.kind}{"}"}`, "{InstanceList}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ParseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%q): %v", tt.template, err)
+			}
+			var out strings.Builder
+			if err := path.Execute(&out, data); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("%s = %q, want %q", tt.template, out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{"unclosed brace", "{.kind", "unclosed '{'"},
+		{"end without range", "{.kind}{end}", "{end} without {range}"},
+		{"range without end", "{range .items[*]}{.id}", "{range} without {end}"},
+		{"unclosed bracket", "{.items[0}", "unclosed '['"},
+		{"unsupported subscript", "{.items[a]}", "unsupported subscript [a]"},
+		{"too many slice parts", "{.items[1:2:3:4]}", "unsupported subscript"},
+		{"invalid slice bound", "{.items[a:2]}", "unsupported subscript"},
+		{"zero slice step", "{.items[::0]}", "slice step must be positive"},
+		{"negative slice step", "{.items[::-1]}", "slice step must be positive"},
+		{"missing field name", "{.items.}", "expected a field name after '.'"},
+		{"missing field after recursive descent", "{.items..}", "expected a field name after '..'"},
+		{"unexpected character", "{.items#}", "unexpected"},
+		{"filter without @", `{.items[?(.id=="a")]}`, "must start with @"},
+		{"unterminated string literal", `{"unterminated}`, "unclosed '{'"},
+		{"bad string literal", `{"\q"}`, "invalid string literal"},
+		{"bad filter operand", `{.items[?(@.id=="\q")]}`, "invalid string literal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJSONPath(tt.template)
+			if err == nil {
+				t.Fatalf("ParseJSONPath(%q): expected an error", tt.template)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseJSONPath(%q) = %v, want an error containing %q", tt.template, err, tt.wantErr)
+			}
+		})
+	}
+}
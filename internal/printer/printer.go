@@ -0,0 +1,400 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+
+// Package printer renders command output in the formats selected with the
+// CLI's -o/--output flag.
+//
+// Commands describe their output twice: as a value that is marshalled for
+// the structured formats (json, yaml, go-template and jsonpath), and as a
+// Table for the tabular ones (table, wide, csv and tsv). Templates are
+// evaluated against the JSON form of the value, so they use the same field
+// names as -o json.
+package printer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the supported output formats
+const (
+	FormatTable      = "table"
+	FormatWide       = "wide"
+	FormatJSON       = "json"
+	FormatYAML       = "yaml"
+	FormatCSV        = "csv"
+	FormatTSV        = "tsv"
+	FormatGoTemplate = "go-template"
+	FormatJSONPath   = "jsonpath"
+)
+
+// Formats lists the accepted --output values, for help text
+const Formats = "table|wide|json|yaml|csv|tsv|go-template=TEMPLATE|jsonpath=EXPRESSION"
+
+// Format is a parsed --output value
+type Format struct {
+	Name string
+
+	goTemplate *template.Template
+	jsonPath   *JSONPath
+}
+
+// ParseFormat parses an --output value. An empty value selects the table
+// format. Templates are compiled here so syntax errors are reported before
+// any API request is made.
+func ParseFormat(value string) (Format, error) {
+	name, arg, hasArg := strings.Cut(value, "=")
+	switch name {
+	case "", FormatTable:
+		name = FormatTable
+	case FormatWide, FormatJSON, FormatYAML, FormatCSV, FormatTSV:
+	case FormatGoTemplate, FormatJSONPath:
+		if !hasArg || arg == "" {
+			return Format{}, fmt.Errorf("output format %s requires a template, e.g. -o '%s=%s'", name, name, exampleTemplate(name))
+		}
+	default:
+		return Format{}, fmt.Errorf("unknown output format '%s'. Must be one of %s", value, Formats)
+	}
+	if hasArg && name != FormatGoTemplate && name != FormatJSONPath {
+		return Format{}, fmt.Errorf("output format %s does not take an argument", name)
+	}
+
+	format := Format{Name: name}
+	switch name {
+	case FormatGoTemplate:
+		tmpl, err := template.New("output").Option("missingkey=zero").Parse(arg)
+		if err != nil {
+			return Format{}, fmt.Errorf("invalid go-template: %v", err)
+		}
+		format.goTemplate = tmpl
+	case FormatJSONPath:
+		jsonPath, err := ParseJSONPath(arg)
+		if err != nil {
+			return Format{}, fmt.Errorf("invalid jsonpath: %v", err)
+		}
+		format.jsonPath = jsonPath
+	}
+	return format, nil
+}
+
+func exampleTemplate(name string) string {
+	if name == FormatGoTemplate {
+		return "{{.id}}"
+	}
+	return "{.id}"
+}
+
+// IsTable reports whether the format is rendered as a human-readable table,
+// in which case the command prints Table.Render output itself.
+func (f Format) IsTable() bool {
+	return f.Name == FormatTable || f.Name == FormatWide
+}
+
+// Wide reports whether wide columns should be shown
+func (f Format) Wide() bool {
+	return f.Name != FormatTable
+}
+
+// Print writes data, or table for csv and tsv, to w. It must not be called
+// for table formats.
+func (f Format) Print(w io.Writer, data interface{}, table *Table) error {
+	switch f.Name {
+	case FormatJSON:
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error formatting JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	case FormatYAML:
+		// Go through JSON so field names match the json format
+		generic, err := toGeneric(data, false)
+		if err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(generic)
+		if err != nil {
+			return fmt.Errorf("error formatting YAML: %w", err)
+		}
+		_, err = w.Write(out)
+		return err
+	case FormatCSV:
+		return table.writeCSV(w)
+	case FormatTSV:
+		return table.writeTSV(w)
+	case FormatGoTemplate:
+		generic, err := toGeneric(data, true)
+		if err != nil {
+			return err
+		}
+		if err := f.goTemplate.Execute(w, generic); err != nil {
+			return fmt.Errorf("error executing go-template: %w", err)
+		}
+		return nil
+	case FormatJSONPath:
+		generic, err := toGeneric(data, true)
+		if err != nil {
+			return err
+		}
+		return f.jsonPath.Execute(w, generic)
+	}
+	return fmt.Errorf("output format %s is rendered by the command", f.Name)
+}
+
+// toGeneric converts data to the maps, slices and scalars of its JSON form.
+// With useNumber, numbers are kept as json.Number so they print exactly as
+// they appear in JSON.
+func toGeneric(data interface{}, useNumber bool) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error formatting output: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if useNumber {
+		decoder.UseNumber()
+	}
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("error formatting output: %w", err)
+	}
+	return generic, nil
+}
+
+// Column is a column of tabular output. Wide columns are left out of the
+// default table format.
+type Column struct {
+	Header string
+	Wide   bool
+	// Volatile columns are not highlighted when they change
+	Volatile bool
+}
+
+// Table is the tabular form of a command's output
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+	// Keys, if set, identify the item shown in each row, so rows can be
+	// matched up between two tables by Highlight
+	Keys []string
+
+	// changed marks the cells to highlight when rendered, per row
+	changed map[int]map[int]bool
+	// added marks rows that were not in the previous table
+	added map[int]bool
+}
+
+// NewTable returns an empty table with the given columns
+func NewTable(columns ...Column) *Table {
+	return &Table{Columns: columns}
+}
+
+// Append adds a row with one value per column
+func (t *Table) Append(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Headers returns the column headers, including wide columns if wide is set
+func (t *Table) Headers(wide bool) []string {
+	var headers []string
+	for _, column := range t.Columns {
+		if wide || !column.Wide {
+			headers = append(headers, column.Header)
+		}
+	}
+	return headers
+}
+
+// row returns the values of row i, including wide columns if wide is set
+func (t *Table) row(i int, wide bool) []string {
+	var values []string
+	for j, column := range t.Columns {
+		if !wide && column.Wide {
+			continue
+		}
+		value := ""
+		if j < len(t.Rows[i]) {
+			value = t.Rows[i][j]
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// Render draws the table to w, including wide columns if wide is set
+func (t *Table) Render(w io.Writer, wide bool) {
+	table := tablewriter.NewWriter(w)
+	table.Header(t.Headers(wide))
+	for i := range t.Rows {
+		table.Append(t.styledRow(i, wide))
+	}
+	table.Render()
+}
+
+// ANSI styles used by Highlight
+const (
+	styleChanged = "\x1b[1;33m" // bold yellow
+	styleAdded   = "\x1b[1;32m" // bold green
+	styleReset   = "\x1b[0m"
+)
+
+// Highlight marks the cells of t that differ from previous so Render shows
+// them in color: rows that are new in t are green and changed cells are
+// yellow. Rows are matched by Keys, so both tables must have them. A nil
+// previous table highlights nothing.
+func (t *Table) Highlight(previous *Table) {
+	t.changed, t.added = nil, nil
+	if previous == nil || t.Keys == nil || previous.Keys == nil {
+		return
+	}
+
+	previousRows := map[string][]string{}
+	for i, key := range previous.Keys {
+		previousRows[key] = previous.Rows[i]
+	}
+
+	t.changed, t.added = map[int]map[int]bool{}, map[int]bool{}
+	for i, key := range t.Keys {
+		before, ok := previousRows[key]
+		if !ok {
+			t.added[i] = true
+			continue
+		}
+		for j, value := range t.Rows[i] {
+			if j < len(t.Columns) && t.Columns[j].Volatile {
+				continue
+			}
+			if j >= len(before) || before[j] != value {
+				if t.changed[i] == nil {
+					t.changed[i] = map[int]bool{}
+				}
+				t.changed[i][j] = true
+			}
+		}
+	}
+}
+
+// HighlightLines is Highlight for text made of "Label: value" lines: it
+// returns text with lines whose label is not in previous in green and lines
+// whose value changed in yellow. Lines are matched by their label, the text
+// before the first ':', so lines that move are not highlighted. Lines with a
+// label in volatile are never highlighted.
+func HighlightLines(text, previous string, volatile ...string) string {
+	// A label that appears more than once is matched by occurrence
+	keys := func(text string) []string {
+		seen := map[string]int{}
+		var keys []string
+		for _, line := range strings.Split(text, "\n") {
+			label, _, _ := strings.Cut(line, ":")
+			seen[label]++
+			keys = append(keys, fmt.Sprintf("%s#%d", label, seen[label]))
+		}
+		return keys
+	}
+
+	before := map[string]string{}
+	previousLines := strings.Split(previous, "\n")
+	for i, key := range keys(previous) {
+		before[key] = previousLines[i]
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, key := range keys(text) {
+		label, _, _ := strings.Cut(lines[i], ":")
+		if slices.Contains(volatile, strings.TrimSpace(label)) {
+			continue
+		}
+		line, ok := before[key]
+		switch {
+		case !ok:
+			lines[i] = style(lines[i], styleAdded)
+		case line != lines[i]:
+			lines[i] = style(lines[i], styleChanged)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// styledRow returns row i like row, with highlighted cells wrapped in ANSI
+// styles
+func (t *Table) styledRow(i int, wide bool) []string {
+	if !t.added[i] && len(t.changed[i]) == 0 {
+		return t.row(i, wide)
+	}
+	var values []string
+	for j, column := range t.Columns {
+		if !wide && column.Wide {
+			continue
+		}
+		value := ""
+		if j < len(t.Rows[i]) {
+			value = t.Rows[i][j]
+		}
+		switch {
+		case t.added[i]:
+			value = style(value, styleAdded)
+		case t.changed[i][j]:
+			value = style(value, styleChanged)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// style wraps each line of value in an ANSI style, so multi-line cells are
+// styled on every line
+func style(value, ansi string) string {
+	if value == "" {
+		return value
+	}
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = ansi + line + styleReset
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (t *Table) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(t.Headers(true)); err != nil {
+		return err
+	}
+	for i := range t.Rows {
+		if err := writer.Write(t.row(i, true)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTSV writes tab-separated values. Unlike csv there is no quoting, so
+// tabs and newlines inside values are replaced with spaces.
+func (t *Table) writeTSV(w io.Writer) error {
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+	writeLine := func(values []string) error {
+		for i, value := range values {
+			values[i] = clean.Replace(value)
+		}
+		_, err := fmt.Fprintln(w, strings.Join(values, "\t"))
+		return err
+	}
+	if err := writeLine(t.Headers(true)); err != nil {
+		return err
+	}
+	for i := range t.Rows {
+		if err := writeLine(t.row(i, true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// BalanceResponse is the response from /billing/get_current_balance
+type BalanceResponse struct {
+	// Credits is the account balance in cents
+	Credits int `json:"credits"`
+}
+
+// UserResponse is the response from /users/me
+type UserResponse struct {
+	Email           string        `json:"email"`
+	Picture         interface{}   `json:"picture"`
+	Provider        string        `json:"provider"`
+	EmailVerified   bool          `json:"email_verified"`
+	Name            string        `json:"name"`
+	PublicKey       string        `json:"public_key"`
+	OnboardedAt     time.Time     `json:"onboarded_at"`
+	OnboardedFor    string        `json:"onboarded_for"`
+	Meta            interface{}   `json:"meta"`
+	ReferralCode    string        `json:"referral_code"`
+	ID              string        `json:"id"`
+	IsActive        bool          `json:"is_active"`
+	APIKey          string        `json:"api_key"`
+	Role            string        `json:"role"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	CompletedPromos []interface{} `json:"completed_promos"`
+	Roles           []interface{} `json:"roles"`
+}
+
+// GetUser returns the account that owns the client's API key
+func (c *Client) GetUser(ctx context.Context) (UserResponse, error) {
+	var user UserResponse
+	err := c.do(ctx, http.MethodGet, "/users/me", nil, &user)
+	return user, err
+}
+
+// GetBalance returns the current credit balance
+func (c *Client) GetBalance(ctx context.Context) (BalanceResponse, error) {
+	var balance BalanceResponse
+	err := c.do(ctx, http.MethodGet, "/billing/get_current_balance", nil, &balance)
+	return balance, err
+}
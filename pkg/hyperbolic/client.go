@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+
+// Package hyperbolic is a Go client for the Hyperbolic GPU marketplace API.
+//
+// It covers the spot marketplace, on-demand virtual machine and bare-metal
+// rentals, and account/billing endpoints used by the hyperbolic CLI.
+package hyperbolic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the production Hyperbolic API endpoint
+const DefaultBaseURL = "https://api.hyperbolic.xyz"
+
+// Client talks to the Hyperbolic API. The zero value is not usable; create
+// one with NewClient.
+type Client struct {
+	// BaseURL is the API root, without a trailing slash
+	BaseURL string
+	// APIKey is sent as a Bearer token. It may be empty for public endpoints
+	// such as the spot marketplace listing.
+	APIKey string
+	// HTTPClient is used to send requests
+	HTTPClient *http.Client
+	// Retry controls how failed requests are retried
+	Retry RetryPolicy
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL overrides the API root
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient overrides the HTTP client used to send requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// NewClient returns a client authenticated with apiKey
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		BaseURL:    DefaultBaseURL,
+		APIKey:     apiKey,
+		HTTPClient: NewHTTPClient(DefaultTimeout),
+		Retry:      DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// do sends a request to path with an optional JSON body and decodes a JSON
+// response into out when out is non-nil. GET requests are retried according
+// to the client's RetryPolicy.
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	return c.send(ctx, method, path, in, out, method == http.MethodGet)
+}
+
+// send is do with explicit control over whether the request is idempotent,
+// for read-only endpoints that use POST.
+func (c *Client) send(ctx context.Context, method, path string, in, out interface{}, idempotent bool) error {
+	body, err := c.doRaw(ctx, method, path, in, idempotent)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error parsing response: %v", err)
+	}
+	return nil
+}
+
+// doRaw sends a request, retrying failed attempts according to the client's
+// RetryPolicy, and returns the raw response body. Any status other than 200
+// or 201 is returned as an *APIError.
+func (c *Client) doRaw(ctx context.Context, method, path string, in interface{}, idempotent bool) ([]byte, error) {
+	var payload []byte
+	if in != nil {
+		var err error
+		payload, err = json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling JSON: %v", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		body, err := c.attempt(ctx, method, path, payload)
+		if err == nil || attempt >= c.Retry.MaxRetries || !shouldRetry(err, idempotent) || ctx.Err() != nil {
+			return body, err
+		}
+
+		timer := time.NewTimer(c.Retry.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends a single request
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, &NetworkError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &NetworkError{Err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	return body, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError is returned when the API responds with a non-success status code
+type APIError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter is the delay requested by a Retry-After header, if any
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+// IsClientError reports whether the API rejected the request (4xx)
+func (e *APIError) IsClientError() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500
+}
+
+// IsServerError reports whether the API failed to handle the request (5xx)
+func (e *APIError) IsServerError() bool {
+	return e.StatusCode >= 500
+}
+
+// IsUnauthorized reports whether the API key was missing, invalid or lacks access
+func (e *APIError) IsUnauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// IsNotFound reports whether the requested resource does not exist
+func (e *APIError) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsInsufficientBalance reports whether the request was rejected because the
+// account does not have enough credits
+func (e *APIError) IsInsufficientBalance() bool {
+	if e.StatusCode == http.StatusPaymentRequired {
+		return true
+	}
+	body := strings.ToLower(e.Body)
+	return e.IsClientError() && (strings.Contains(body, "insufficient") || strings.Contains(body, "not enough credits"))
+}
+
+// IsCapacityUnavailable reports whether a rental was rejected because the
+// node no longer has enough free GPUs, in which case another node may still
+// accept the same request. The API reports this with a 409 or with a 400
+// whose message says so.
+func (e *APIError) IsCapacityUnavailable() bool {
+	if e.StatusCode == http.StatusConflict {
+		return true
+	}
+	if !e.IsClientError() || e.IsUnauthorized() || e.IsInsufficientBalance() || e.StatusCode == http.StatusTooManyRequests {
+		return false
+	}
+	body := strings.ToLower(e.Body)
+	return strings.Contains(body, "not enough") || strings.Contains(body, "available")
+}
+
+// NetworkError is returned when a request could not be sent or its response
+// could not be read
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("network error: %v", e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
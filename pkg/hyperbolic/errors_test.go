@@ -0,0 +1,41 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+func TestIsCapacityUnavailable(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   bool
+	}{
+		{http.StatusConflict, `{"message": "conflict"}`, true},
+		{http.StatusBadRequest, `{"message": "not enough GPUs available on node"}`, true},
+		{http.StatusBadRequest, `{"message": "Node is no longer available"}`, true},
+
+		// The request itself is at fault, so every node would reject it
+		{http.StatusBadRequest, `{"message": "invalid gpu_count"}`, false},
+		{http.StatusNotFound, `{"message": "node not found"}`, false},
+		{http.StatusUnprocessableEntity, `{"message": "unknown image"}`, false},
+
+		// Account and rate limits
+		{http.StatusUnauthorized, `{"message": "not available"}`, false},
+		{http.StatusPaymentRequired, `{"message": "payment required"}`, false},
+		{http.StatusBadRequest, `{"message": "not enough credits"}`, false},
+		{http.StatusTooManyRequests, `{"message": "no capacity available"}`, false},
+		{http.StatusServiceUnavailable, `{"message": "service unavailable"}`, false},
+	}
+	for _, tt := range tests {
+		err := &hyperbolic.APIError{StatusCode: tt.status, Body: tt.body}
+		if got := err.IsCapacityUnavailable(); got != tt.want {
+			t.Errorf("%d %s: got %v, want %v", tt.status, tt.body, got, tt.want)
+		}
+	}
+}
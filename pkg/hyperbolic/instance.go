@@ -0,0 +1,480 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InstanceType distinguishes the kinds of rental
+type InstanceType string
+
+const (
+	InstanceTypeSpot           InstanceType = "spot"
+	InstanceTypeVirtualMachine InstanceType = "virtual-machine"
+	InstanceTypeBareMetal      InstanceType = "bare-metal"
+)
+
+// ErrInstanceNotFound is returned by GetInstance when the caller has no
+// rental with the given ID
+var ErrInstanceNotFound = errors.New("instance not found")
+
+// Instance is a spot, virtual machine or bare-metal rental in a common form.
+// Use NewSpotInstance and NewOnDemandInstance to build one from API
+// responses.
+type Instance struct {
+	ID     string
+	Type   InstanceType
+	Status string
+	// Name is the on-demand rental's name, if any
+	Name string
+
+	// GPUModel is empty if the API did not report it
+	GPUModel    string
+	GPUCount    int // across all nodes
+	GPUsPerNode int
+	NodeCount   int
+	// GPUMemoryMB is the memory of each GPU, if reported
+	GPUMemoryMB int
+
+	// Per-node hardware, zero if not reported
+	CPUCount  int
+	CPUModel  string
+	RAMGB     int
+	StorageGB int
+
+	NetworkType     string
+	OperatingSystem string
+
+	Nodes []InstanceNode
+	Ports []InstancePort
+
+	// CostPerHour is the total hourly cost in US dollars
+	CostPerHour float64
+
+	// Timestamps are zero if unknown
+	CreatedAt time.Time
+	StartedAt time.Time
+	EndedAt   time.Time
+
+	// Exactly one of these holds the API response the instance was built from
+	Spot     *UserInstance
+	OnDemand *OnDemandInstance
+}
+
+// InstanceNode is one machine of an instance
+type InstanceNode struct {
+	PublicIP  string
+	PrivateIP string
+	// SSH is nil until the node can be reached
+	SSH *SSHEndpoint
+}
+
+// SSHEndpoint is where to connect to a node with ssh
+type SSHEndpoint struct {
+	User string
+	Host string
+	Port int
+	// Command is the ssh command line reported by the API, or one built
+	// from User, Host and Port
+	Command string
+}
+
+// Destination returns the user@host argument for ssh, or just the host if the
+// user is not known
+func (e SSHEndpoint) Destination() string {
+	if e.User == "" {
+		return e.Host
+	}
+	return e.User + "@" + e.Host
+}
+
+// InstancePort is a port exposed by an instance
+type InstancePort struct {
+	// Port is the publicly reachable port
+	Port int
+	// InternalPort is the port on the instance, if different
+	InternalPort int
+	Protocol     string
+	URL          string
+}
+
+// NewSpotInstance converts a spot rental
+func NewSpotInstance(rental UserInstance) Instance {
+	instance := Instance{
+		ID:          rental.ID,
+		Type:        InstanceTypeSpot,
+		Status:      rental.Instance.Status,
+		GPUCount:    rental.Instance.GPUCount,
+		GPUsPerNode: rental.Instance.GPUCount,
+		NodeCount:   1,
+		Nodes:       []InstanceNode{},
+		Ports:       []InstancePort{},
+		// The price is per GPU, in cents
+		CostPerHour: rental.Instance.Pricing.Price.Amount / 100.0 * float64(rental.Instance.GPUCount),
+		CreatedAt:   parseTimestamp(rental.Created),
+		StartedAt:   parseTimestamp(rental.Start),
+		Spot:        &rental,
+	}
+	if len(rental.Instance.Hardware.GPUs) > 0 {
+		instance.GPUModel = rental.Instance.Hardware.GPUs[0].Model
+		instance.GPUMemoryMB = rental.Instance.Hardware.GPUs[0].RAM
+	}
+	if rental.End != nil {
+		instance.EndedAt = parseTimestamp(*rental.End)
+	}
+	if ssh := ParseSSHCommand(rental.SSHCommand); ssh != nil {
+		instance.Nodes = append(instance.Nodes, InstanceNode{SSH: ssh})
+	}
+	for _, mapping := range rental.PortMappings {
+		instance.Ports = append(instance.Ports, InstancePort{
+			Port:     mapping.Port,
+			Protocol: mapping.Protocol,
+			URL:      mapping.Protocol + "://" + mapping.Domain + ":" + strconv.Itoa(mapping.Port),
+		})
+	}
+	return instance
+}
+
+// NewOnDemandInstance converts a virtual machine or bare-metal rental.
+// instanceType says which endpoint it was listed by.
+func NewOnDemandInstance(rental OnDemandInstance, instanceType InstanceType) Instance {
+	meta := rental.Meta
+	instance := Instance{
+		ID:              strconv.Itoa(rental.ID),
+		Type:            instanceType,
+		Status:          rental.Status,
+		Name:            meta.Name,
+		NetworkType:     meta.NetworkType,
+		OperatingSystem: meta.OperatingSystem,
+		Nodes:           []InstanceNode{},
+		Ports:           []InstancePort{},
+		CostPerHour:     float64(rental.CostPerHour) / 100.0,
+		CreatedAt:       parseTimestamp(rental.CreatedAt),
+		StartedAt:       parseTimestamp(rental.StartedAt),
+		OnDemand:        &rental,
+	}
+	if rental.TerminatedAt != nil {
+		instance.EndedAt = parseTimestamp(*rental.TerminatedAt)
+	}
+
+	switch {
+	case meta.SpecsPerNode != nil:
+		instance.GPUModel = meta.SpecsPerNode.GPUModel
+		instance.GPUsPerNode = meta.SpecsPerNode.GPUCount
+		instance.CPUCount = meta.SpecsPerNode.CPUCount
+		instance.CPUModel = meta.SpecsPerNode.CPUModel
+		instance.RAMGB = meta.SpecsPerNode.RAMGb
+		instance.StorageGB = meta.SpecsPerNode.StorageGb
+	case meta.Resources != nil:
+		// Report the first model by name so the result is stable
+		models := make([]string, 0, len(meta.Resources.GPUs))
+		for model, gpu := range meta.Resources.GPUs {
+			models = append(models, model)
+			instance.GPUsPerNode += gpu.Count
+		}
+		sort.Strings(models)
+		if len(models) > 0 {
+			instance.GPUModel = models[0]
+		}
+		instance.CPUCount = meta.Resources.VCPUCount
+		instance.RAMGB = meta.Resources.RAMGb
+		instance.StorageGB = meta.Resources.StorageGb
+	}
+	if instance.GPUsPerNode == 0 {
+		instance.GPUsPerNode = meta.GPUCount
+	}
+	instance.NodeCount = 1
+	if meta.NodeCount > 1 {
+		instance.NodeCount = meta.NodeCount
+	}
+	instance.GPUCount = instance.GPUsPerNode * instance.NodeCount
+
+	if len(meta.NodeNetworking) > 0 {
+		for _, network := range meta.NodeNetworking {
+			node := InstanceNode{PublicIP: network.PublicIP, PrivateIP: network.PrivateIP}
+			if meta.Username != "" && network.PublicIP != "" {
+				node.SSH = &SSHEndpoint{
+					User:    meta.Username,
+					Host:    network.PublicIP,
+					Port:    22,
+					Command: "ssh " + meta.Username + "@" + network.PublicIP,
+				}
+			}
+			instance.Nodes = append(instance.Nodes, node)
+		}
+	} else if meta.PublicIP != "" || meta.InternalIP != "" || meta.SSHCommand != "" {
+		instance.Nodes = append(instance.Nodes, InstanceNode{
+			PublicIP:  meta.PublicIP,
+			PrivateIP: meta.InternalIP,
+			SSH:       ParseSSHCommand(meta.SSHCommand),
+		})
+	}
+
+	for _, portForward := range meta.PortForwards {
+		instance.Ports = append(instance.Ports, InstancePort{Port: portForward.ExternalPort, InternalPort: portForward.InternalPort})
+	}
+	return instance
+}
+
+// IsStarting reports whether the instance is still being provisioned
+func (i Instance) IsStarting() bool {
+	switch strings.ToLower(i.Status) {
+	case "pending", "starting", "provisioning", "initializing":
+		return true
+	}
+	return false
+}
+
+// Uptime returns how long the instance has been running: until it ended,
+// or until now. It returns false if the start time is unknown.
+func (i Instance) Uptime() (time.Duration, bool) {
+	if i.StartedAt.IsZero() {
+		return 0, false
+	}
+	end := i.EndedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.Sub(i.StartedAt), true
+}
+
+// SSHEndpoints returns the SSH endpoints of the nodes that have one
+func (i Instance) SSHEndpoints() []SSHEndpoint {
+	var endpoints []SSHEndpoint
+	for _, node := range i.Nodes {
+		if node.SSH != nil {
+			endpoints = append(endpoints, *node.SSH)
+		}
+	}
+	return endpoints
+}
+
+// ListInstances returns every spot, virtual machine and bare-metal rental
+func (c *Client) ListInstances(ctx context.Context) ([]Instance, error) {
+	spot, err := c.ListSpotInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vms, err := c.ListVirtualMachineRentals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bareMetal, err := c.ListBareMetalRentals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]Instance, 0, len(spot.Instances)+len(vms)+len(bareMetal))
+	for _, rental := range spot.Instances {
+		instances = append(instances, NewSpotInstance(rental))
+	}
+	for _, rental := range vms {
+		instances = append(instances, NewOnDemandInstance(rental, InstanceTypeVirtualMachine))
+	}
+	for _, rental := range bareMetal {
+		instances = append(instances, NewOnDemandInstance(rental, InstanceTypeBareMetal))
+	}
+	return instances, nil
+}
+
+// GetInstance returns the rental with the given ID, or ErrInstanceNotFound.
+// On-demand rentals have numeric IDs, so only the listings that could
+// contain id are fetched.
+func (c *Client) GetInstance(ctx context.Context, id string) (Instance, error) {
+	rentalID, err := strconv.Atoi(id)
+	if err != nil {
+		spot, err := c.ListSpotInstances(ctx)
+		if err != nil {
+			return Instance{}, err
+		}
+		for _, rental := range spot.Instances {
+			if rental.ID == id {
+				return NewSpotInstance(rental), nil
+			}
+		}
+		return Instance{}, ErrInstanceNotFound
+	}
+
+	// Check VM rentals first. Only report not found if both listings could
+	// be checked.
+	vms, vmErr := c.ListVirtualMachineRentals(ctx)
+	for _, rental := range vms {
+		if rental.ID == rentalID {
+			return NewOnDemandInstance(rental, InstanceTypeVirtualMachine), nil
+		}
+	}
+	bareMetal, bmErr := c.ListBareMetalRentals(ctx)
+	for _, rental := range bareMetal {
+		if rental.ID == rentalID {
+			return NewOnDemandInstance(rental, InstanceTypeBareMetal), nil
+		}
+	}
+	if vmErr != nil {
+		return Instance{}, vmErr
+	}
+	if bmErr != nil {
+		return Instance{}, bmErr
+	}
+	return Instance{}, ErrInstanceNotFound
+}
+
+// TerminateInstance terminates a rental using the endpoint for its type
+func (c *Client) TerminateInstance(ctx context.Context, instance Instance) error {
+	switch instance.Type {
+	case InstanceTypeSpot:
+		return c.TerminateSpotInstance(ctx, instance.ID)
+	case InstanceTypeVirtualMachine, InstanceTypeBareMetal:
+		rentalID, err := strconv.Atoi(instance.ID)
+		if err != nil {
+			return errors.New("invalid on-demand instance ID " + instance.ID)
+		}
+		if instance.Type == InstanceTypeVirtualMachine {
+			return c.TerminateVirtualMachineRental(ctx, rentalID)
+		}
+		return c.TerminateBareMetalRental(ctx, rentalID)
+	}
+	return errors.New("unknown instance type " + string(instance.Type))
+}
+
+// sshOptionsWithValue are the ssh client options that take an argument
+const sshOptionsWithValue = "BbcDEeFIiJLlmOoPpQRSWw"
+
+// ParseSSHCommand splits a command such as "ssh ubuntu@host -p 31001" into
+// an SSHEndpoint. It returns nil if command is not an ssh command.
+//
+// Options are read as ssh reads them, before or after the destination: the
+// values of options such as -i or -J are skipped, values may be attached
+// ("-p31001"), -l and "-o User=" set the user and "-o Port=" the port. The
+// remote command, from the first argument after the destination that is not
+// an option, is ignored.
+func ParseSSHCommand(command string) *SSHEndpoint {
+	fields := strings.Fields(command)
+	if len(fields) < 2 || fields[0] != "ssh" {
+		return nil
+	}
+	ssh := &SSHEndpoint{Port: 22, Command: command}
+	user, port := "", 0
+
+	setOption := func(option byte, value string) {
+		switch option {
+		case 'p':
+			if n, err := strconv.Atoi(value); err == nil {
+				port = n
+			}
+		case 'l':
+			user = value
+		case 'o':
+			key, val, _ := strings.Cut(value, "=")
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "port":
+				if n, err := strconv.Atoi(strings.TrimSpace(val)); err == nil && port == 0 {
+					port = n
+				}
+			case "user":
+				if user == "" {
+					user = strings.TrimSpace(val)
+				}
+			}
+		}
+	}
+
+	// As in ssh, options may come before or after the destination, and the
+	// first argument after it that is not an option starts the remote command
+	destination := ""
+	terminated := false
+	for i := 1; i < len(fields); i++ {
+		field := fields[i]
+		if field == "--" && !terminated {
+			terminated = true
+			continue
+		}
+		if terminated || len(field) < 2 || field[0] != '-' {
+			if destination != "" {
+				break
+			}
+			destination = field
+			if terminated {
+				break
+			}
+			continue
+		}
+		// Flags may be grouped, as in -tt or -vp 2222
+		for j := 1; j < len(field); j++ {
+			if !strings.ContainsRune(sshOptionsWithValue, rune(field[j])) {
+				continue
+			}
+			value := field[j+1:]
+			if value == "" {
+				if i+1 >= len(fields) {
+					return nil
+				}
+				i++
+				value = fields[i]
+			}
+			setOption(field[j], value)
+			break
+		}
+	}
+	if destination == "" {
+		return nil
+	}
+
+	if rest, ok := strings.CutPrefix(destination, "ssh://"); ok {
+		// ssh://[user@]host[:port]
+		at := strings.LastIndex(rest, "@") + 1
+		host, portText, hasPort := strings.Cut(rest[at:], ":")
+		if n, err := strconv.Atoi(portText); hasPort && err == nil && port == 0 {
+			port = n
+		}
+		destination = rest[:at] + host
+	}
+	if at := strings.LastIndex(destination, "@"); at >= 0 {
+		ssh.User, ssh.Host = destination[:at], destination[at+1:]
+	} else {
+		ssh.Host = destination
+	}
+	// As in ssh, -l and "-o User=" win over a user in the destination
+	if user != "" {
+		ssh.User = user
+	}
+	if port != 0 {
+		ssh.Port = port
+	}
+	if ssh.Host == "" {
+		return nil
+	}
+	return ssh
+}
+
+// timestampLayouts are the formats timestamps are returned in by the API
+var timestampLayouts = []string{
+	// RFC3339 (e.g., "2006-01-02T15:04:05Z07:00")
+	time.RFC3339,
+	// Basic ISO format (e.g., "2006-01-02T15:04:05Z")
+	"2006-01-02T15:04:05Z",
+	// On-demand API format (e.g., "2025-07-08 21:53:35.367+00")
+	"2006-01-02 15:04:05.999+00",
+	// On-demand API format without microseconds (e.g., "2025-07-08 21:53:35+00")
+	"2006-01-02 15:04:05+00",
+}
+
+// parseTimestamp parses a timestamp in any of the formats used by the API,
+// returning the zero time if it is empty or not recognised
+func parseTimestamp(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
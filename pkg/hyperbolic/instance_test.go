@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic_test
+
+import (
+	"testing"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+func TestParseSSHCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		user    string
+		host    string
+		port    int
+	}{
+		{"ssh ubuntu@host.example.com", "ubuntu", "host.example.com", 22},
+		{"ssh ubuntu@host.example.com -p 31001", "ubuntu", "host.example.com", 31001},
+		{"ssh -p 31001 ubuntu@host.example.com", "ubuntu", "host.example.com", 31001},
+		{"ssh -p31001 ubuntu@host.example.com", "ubuntu", "host.example.com", 31001},
+		{"ssh 203.0.113.3", "", "203.0.113.3", 22},
+
+		// Values of options are not the destination
+		{"ssh -i ~/.ssh/key ubuntu@host -p 2222", "ubuntu", "host", 2222},
+		{"ssh -o StrictHostKeyChecking=no ubuntu@host", "ubuntu", "host", 22},
+		{"ssh -J jump@bastion:22 ubuntu@10.0.0.5", "ubuntu", "10.0.0.5", 22},
+		{"ssh -F /dev/null -o UserKnownHostsFile=/dev/null ubuntu@host -p 31001", "ubuntu", "host", 31001},
+		{"ssh -L 8888:localhost:8888 -N ubuntu@host", "ubuntu", "host", 22},
+		{"ssh -i key -l root host", "root", "host", 22},
+		{"ssh -lroot host", "root", "host", 22},
+
+		// Grouped flags
+		{"ssh -tt ubuntu@host", "ubuntu", "host", 22},
+		{"ssh -vp 2222 ubuntu@host", "ubuntu", "host", 2222},
+		{"ssh -vp2222 ubuntu@host", "ubuntu", "host", 2222},
+		{"ssh -Ai key ubuntu@host", "ubuntu", "host", 22},
+
+		// -o Port and -o User
+		{"ssh -o Port=2222 ubuntu@host", "ubuntu", "host", 2222},
+		{"ssh -oPort=2222 host", "", "host", 2222},
+		{"ssh -o User=admin host", "admin", "host", 22},
+		{"ssh -o Port=2222 -p 3333 host", "", "host", 3333},
+		{"ssh -p 3333 -o Port=2222 host", "", "host", 3333},
+
+		// As in ssh, -l wins over the destination's user
+		{"ssh -l root ubuntu@host", "root", "host", 22},
+
+		// The remote command is not parsed
+		{"ssh ubuntu@host -- ls -p 9", "ubuntu", "host", 22},
+		{"ssh ubuntu@host nvidia-smi -l 1", "ubuntu", "host", 22},
+		{"ssh -- ubuntu@host", "ubuntu", "host", 22},
+
+		// ssh:// destinations
+		{"ssh ssh://ubuntu@host:2222", "ubuntu", "host", 2222},
+		{"ssh -p 3333 ssh://host:2222", "", "host", 3333},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			ssh := hyperbolic.ParseSSHCommand(tt.command)
+			if ssh == nil {
+				t.Fatal("expected an endpoint, got nil")
+			}
+			if ssh.User != tt.user || ssh.Host != tt.host || ssh.Port != tt.port {
+				t.Errorf("got user %q host %q port %d, want user %q host %q port %d", ssh.User, ssh.Host, ssh.Port, tt.user, tt.host, tt.port)
+			}
+			if ssh.Command != tt.command {
+				t.Errorf("Command = %q, want %q", ssh.Command, tt.command)
+			}
+		})
+	}
+}
+
+func TestParseSSHCommandInvalid(t *testing.T) {
+	for _, command := range []string{
+		"",
+		"ssh",
+		"scp file host:",
+		"mosh ubuntu@host",
+		"ssh -p 2222",
+		"ssh -i",
+		"ssh -i key",
+		"ssh ubuntu@",
+		"ssh --",
+	} {
+		t.Run(command, func(t *testing.T) {
+			if ssh := hyperbolic.ParseSSHCommand(command); ssh != nil {
+				t.Errorf("expected nil, got %+v", *ssh)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"context"
+	"net/http"
+)
+
+// InstancesResponse is the response from /v1/marketplace/instances (spot rentals)
+type InstancesResponse struct {
+	Instances []UserInstance `json:"instances"`
+}
+
+// UserInstance is a rented spot instance
+type UserInstance struct {
+	ID           string              `json:"id"`
+	Start        string              `json:"start"`
+	End          *string             `json:"end"`
+	Created      string              `json:"created"`
+	SSHCommand   string              `json:"sshCommand"`
+	PortMappings []PortMapping       `json:"portMappings"`
+	Instance     UserInstanceDetails `json:"instance"`
+}
+
+type PortMapping struct {
+	Domain   string `json:"domain"`
+	Protocol string `json:"protocol"`
+	Port     int    `json:"port"`
+}
+
+type UserInstanceDetails struct {
+	ID       string               `json:"id"`
+	Status   string               `json:"status"`
+	Hardware UserInstanceHardware `json:"hardware"`
+	Pricing  UserInstancePricing  `json:"pricing"`
+	GPUCount int                  `json:"gpu_count"`
+}
+
+type UserInstanceHardware struct {
+	GPUs []UserInstanceGPU `json:"gpus"`
+}
+
+type UserInstanceGPU struct {
+	Model string `json:"model"`
+	RAM   int    `json:"ram"`
+}
+
+type UserInstancePricing struct {
+	Price UserInstancePrice `json:"price"`
+}
+
+// UserInstancePrice is a per-GPU price in cents
+type UserInstancePrice struct {
+	Amount float64 `json:"amount"`
+	Period string  `json:"period"`
+}
+
+// OnDemandInstance is a virtual machine or bare-metal rental
+type OnDemandInstance struct {
+	ID             int     `json:"id"`
+	CreatedAt      string  `json:"createdAt"`
+	UpdatedAt      *string `json:"updatedAt"`
+	DeletedAt      *string `json:"deletedAt"`
+	UserID         string  `json:"userId"`
+	StartedAt      string  `json:"startedAt"`
+	TerminatedAt   *string `json:"terminatedAt"`
+	ExternalID     string  `json:"externalId"`
+	RentalProvider string  `json:"rentalProvider"`
+	// CostPerHour is the total rental cost in cents
+	CostPerHour int                  `json:"costPerHour"`
+	Status      string               `json:"status"`
+	Meta        OnDemandInstanceMeta `json:"meta"`
+}
+
+type OnDemandInstanceMeta struct {
+	Name              string                     `json:"name"`
+	Tags              []string                   `json:"tags,omitempty"`
+	Type              string                     `json:"type,omitempty"`
+	PublicIP          string                     `json:"public_ip,omitempty"`
+	GPUCount          int                        `json:"gpu_count,omitempty"`
+	Resources         *OnDemandInstanceResources `json:"resources,omitempty"`
+	HostnodeID        string                     `json:"hostnode_id,omitempty"`
+	InternalIP        string                     `json:"internal_ip,omitempty"`
+	RentalType        string                     `json:"rental_type"`
+	SSHCommand        string                     `json:"ssh_command,omitempty"`
+	PortForwards      []OnDemandPortForward      `json:"port_forwards,omitempty"`
+	OperatingSystem   string                     `json:"operating_system,omitempty"`
+	TimestampCreation string                     `json:"timestamp_creation,omitempty"`
+	// Bare metal specific fields
+	SubOrder          *string                `json:"sub_order,omitempty"`
+	NodeCount         int                    `json:"node_count,omitempty"`
+	NetworkType       string                 `json:"network_type,omitempty"`
+	SpecsPerNode      *OnDemandInstanceSpecs `json:"specs_per_node,omitempty"`
+	Username          string                 `json:"username,omitempty"`
+	NodeNetworking    []OnDemandNetworking   `json:"node_networking,omitempty"`
+	CreationTimestamp string                 `json:"creation_timestamp,omitempty"`
+}
+
+type OnDemandInstanceResources struct {
+	RAMGb     int                    `json:"ram_gb"`
+	StorageGb int                    `json:"storage_gb"`
+	VCPUCount int                    `json:"vcpu_count"`
+	GPUs      map[string]OnDemandGPU `json:"gpus"`
+}
+
+type OnDemandGPU struct {
+	Count int `json:"count"`
+}
+
+type OnDemandInstanceSpecs struct {
+	RAMGb     int    `json:"ram_gb"`
+	CPUCount  int    `json:"cpu_count"`
+	CPUModel  string `json:"cpu_model"`
+	GPUCount  int    `json:"gpu_count"`
+	GPUModel  string `json:"gpu_model"`
+	StorageGb int    `json:"storage_gb"`
+}
+
+type OnDemandNetworking struct {
+	PublicIP  string `json:"public_ip"`
+	PrivateIP string `json:"private_ip"`
+}
+
+type OnDemandPortForward struct {
+	ExternalPort int `json:"external_port"`
+	InternalPort int `json:"internal_port"`
+}
+
+// ListSpotInstances returns the caller's spot rentals
+func (c *Client) ListSpotInstances(ctx context.Context) (InstancesResponse, error) {
+	var instances InstancesResponse
+	err := c.do(ctx, http.MethodGet, "/v1/marketplace/instances", nil, &instances)
+	return instances, err
+}
+
+// ListVirtualMachineRentals returns the caller's on-demand virtual machine rentals
+func (c *Client) ListVirtualMachineRentals(ctx context.Context) ([]OnDemandInstance, error) {
+	var instances []OnDemandInstance
+	err := c.do(ctx, http.MethodGet, "/v2/marketplace/virtual-machine-rentals", nil, &instances)
+	return instances, err
+}
+
+// ListBareMetalRentals returns the caller's on-demand bare-metal rentals
+func (c *Client) ListBareMetalRentals(ctx context.Context) ([]OnDemandInstance, error) {
+	var instances []OnDemandInstance
+	err := c.do(ctx, http.MethodGet, "/v2/marketplace/bare-metal-rentals", nil, &instances)
+	return instances, err
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// MarketplaceRequest is the request body for /v1/marketplace
+type MarketplaceRequest struct {
+	Filters map[string]interface{} `json:"filters"`
+}
+
+// MarketplaceResponse is the response from /v1/marketplace
+type MarketplaceResponse struct {
+	Instances []MarketplaceInstance `json:"instances"`
+}
+
+// MarketplaceInstance is a spot node listed on the marketplace
+type MarketplaceInstance struct {
+	ID           string   `json:"id"`
+	Status       string   `json:"status"`
+	Hardware     Hardware `json:"hardware"`
+	GpusTotal    int      `json:"gpus_total"`
+	GpusReserved int      `json:"gpus_reserved"`
+	Location     Location `json:"location"`
+	Pricing      Pricing  `json:"pricing"`
+	ClusterName  string   `json:"cluster_name"`
+	SupplierID   string   `json:"supplier_id"`
+}
+
+type Hardware struct {
+	CPUs    []CPU     `json:"cpus"`
+	GPUs    []GPU     `json:"gpus"`
+	Storage []Storage `json:"storage"`
+	RAM     []RAM     `json:"ram"`
+}
+
+type CPU struct {
+	Model        string `json:"model"`
+	VirtualCores int    `json:"virtual_cores"`
+}
+
+type GPU struct {
+	Model     string `json:"model"`
+	RAM       int    `json:"ram"`
+	Interface string `json:"interface"`
+}
+
+type Storage struct {
+	Capacity int `json:"capacity"`
+}
+
+type RAM struct {
+	Capacity int `json:"capacity"`
+}
+
+type Location struct {
+	Region string `json:"region"`
+}
+
+type Pricing struct {
+	Price Price `json:"price"`
+}
+
+// Price is a per-GPU price in cents
+type Price struct {
+	Amount int    `json:"amount"`
+	Period string `json:"period"`
+	Agent  string `json:"agent"`
+}
+
+// ListMarketplace returns the spot nodes on the marketplace matching filters.
+// A nil filters map lists every node.
+func (c *Client) ListMarketplace(ctx context.Context, filters map[string]interface{}) (MarketplaceResponse, error) {
+	if filters == nil {
+		filters = map[string]interface{}{}
+	}
+	var marketplace MarketplaceResponse
+	// The listing is read-only, so it is safe to retry despite using POST
+	err := c.send(ctx, http.MethodPost, "/v1/marketplace", MarketplaceRequest{Filters: filters}, &marketplace, true)
+	return marketplace, err
+}
+
+// AvailableGPUs returns the number of GPUs on the node that are not rented
+func (m MarketplaceInstance) AvailableGPUs() int {
+	return m.GpusTotal - m.GpusReserved
+}
+
+// GPU returns the node's GPU, or a zero GPU if none is listed
+func (m MarketplaceInstance) GPU() GPU {
+	if len(m.Hardware.GPUs) > 0 {
+		return m.Hardware.GPUs[0]
+	}
+	return GPU{}
+}
+
+// CPU returns the node's CPU, or a zero CPU if none is listed
+func (m MarketplaceInstance) CPU() CPU {
+	if len(m.Hardware.CPUs) > 0 {
+		return m.Hardware.CPUs[0]
+	}
+	return CPU{}
+}
+
+// RAMGB returns the node's RAM in GB, or 0 if none is listed
+func (m MarketplaceInstance) RAMGB() int {
+	if len(m.Hardware.RAM) > 0 {
+		return m.Hardware.RAM[0].Capacity
+	}
+	return 0
+}
+
+// StorageGB returns the node's storage in GB, or 0 if none is listed
+func (m MarketplaceInstance) StorageGB() int {
+	if len(m.Hardware.Storage) > 0 {
+		return m.Hardware.Storage[0].Capacity
+	}
+	return 0
+}
+
+// MarketplaceFilter selects spot nodes. Zero fields match every node.
+//
+// The marketplace endpoint's own filters match exactly, including case, so
+// nodes are listed unfiltered and checked with Match instead.
+type MarketplaceFilter struct {
+	// GPUModel matches GPU models containing it, ignoring case, so "h100"
+	// matches "NVIDIA-H100-80GB-HBM3"
+	GPUModel string
+	// MinGPUs is the minimum number of available GPUs
+	MinGPUs int
+	// MaxPrice is the maximum price per GPU per hour in cents
+	MaxPrice int
+	// Region and Cluster must match exactly, ignoring case
+	Region  string
+	Cluster string
+	// GPUInterface matches interfaces containing it, ignoring case, so
+	// "pcie" matches "PCIeX16"
+	GPUInterface string
+	MinRAMGB     int
+	MinStorageGB int
+	MinCPUCores  int
+}
+
+// Match reports whether a node meets every condition of the filter
+func (f MarketplaceFilter) Match(instance MarketplaceInstance) bool {
+	gpu := instance.GPU()
+	switch {
+	case f.GPUModel != "" && !containsFold(gpu.Model, f.GPUModel):
+		return false
+	case f.GPUInterface != "" && !containsFold(gpu.Interface, f.GPUInterface):
+		return false
+	case f.Region != "" && !strings.EqualFold(instance.Location.Region, f.Region):
+		return false
+	case f.Cluster != "" && !strings.EqualFold(instance.ClusterName, f.Cluster):
+		return false
+	case f.MaxPrice > 0 && instance.Pricing.Price.Amount > f.MaxPrice:
+		return false
+	case instance.AvailableGPUs() < f.MinGPUs:
+		return false
+	case instance.RAMGB() < f.MinRAMGB, instance.StorageGB() < f.MinStorageGB, instance.CPU().VirtualCores < f.MinCPUCores:
+		return false
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
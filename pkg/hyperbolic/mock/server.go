@@ -0,0 +1,501 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+
+// Package mock provides an in-memory stand-in for the Hyperbolic API.
+//
+// The server serves canned marketplace, rental and billing data and keeps
+// track of rentals created and terminated through it, so the CLI and the
+// hyperbolic client can be exercised end-to-end without network access.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+)
+
+// Server is an in-memory Hyperbolic API. Create one with NewServer.
+type Server struct {
+	// APIKey, when non-empty, is the only Bearer token accepted on
+	// authenticated endpoints. Any non-empty token is accepted otherwise.
+	APIKey string
+	// ProvisionDelay is how long new rentals report a "starting" status
+	// before switching to "running"
+	ProvisionDelay time.Duration
+
+	mu          sync.Mutex
+	mux         *http.ServeMux
+	nextID      int
+	user        hyperbolic.UserResponse
+	balance     hyperbolic.BalanceResponse
+	nodes       []hyperbolic.MarketplaceInstance
+	spot        []hyperbolic.UserInstance
+	spotNodes   map[string]int
+	vms         []hyperbolic.OnDemandInstance
+	bareMetal   []hyperbolic.OnDemandInstance
+	vmOptions   hyperbolic.VirtualMachineOptions
+	bmOptions   hyperbolic.BareMetalOptions
+	provisioned map[string]time.Time
+}
+
+// NewServer returns a server seeded with canned marketplace and account data
+func NewServer() *Server {
+	s := &Server{
+		nextID:      1000,
+		spotNodes:   map[string]int{},
+		provisioned: map[string]time.Time{},
+		user: hyperbolic.UserResponse{
+			ID:            "mock-user",
+			Email:         "dev@example.com",
+			Name:          "Mock User",
+			EmailVerified: true,
+			IsActive:      true,
+			Role:          "user",
+		},
+		balance: hyperbolic.BalanceResponse{Credits: 10000},
+		nodes:   defaultNodes(),
+		vmOptions: hyperbolic.VirtualMachineOptions{
+			{GPUCount: 1, CostPerHour: 1.49},
+			{GPUCount: 2, CostPerHour: 1.49},
+			{GPUCount: 4, CostPerHour: 1.49},
+			{GPUCount: 8, CostPerHour: 1.49},
+		},
+		bmOptions: hyperbolic.BareMetalOptions{
+			Ethernet:   hyperbolic.BareMetalNetworkOption{GPUCount: 64, CostPerHour: 1.69},
+			Infiniband: hyperbolic.BareMetalNetworkOption{GPUCount: 32, CostPerHour: 2.19},
+		},
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /v1/marketplace", s.handleMarketplace)
+	s.mux.HandleFunc("GET /users/me", s.authenticated(s.handleUser))
+	s.mux.HandleFunc("GET /billing/get_current_balance", s.authenticated(s.handleBalance))
+	s.mux.HandleFunc("GET /v1/marketplace/instances", s.authenticated(s.handleListSpot))
+	s.mux.HandleFunc("POST /v1/marketplace/instances/create", s.authenticated(s.handleRentSpot))
+	s.mux.HandleFunc("POST /v1/marketplace/instances/terminate", s.authenticated(s.handleTerminateSpot))
+	s.mux.HandleFunc("GET /v2/marketplace/virtual-machine-options", s.authenticated(s.handleVMOptions))
+	s.mux.HandleFunc("GET /v2/marketplace/bare-metal-options", s.authenticated(s.handleBMOptions))
+	s.mux.HandleFunc("GET /v2/marketplace/virtual-machine-rentals", s.authenticated(s.handleListVMs))
+	s.mux.HandleFunc("POST /v2/marketplace/virtual-machine-rentals", s.authenticated(s.handleRentVM))
+	s.mux.HandleFunc("POST /v2/marketplace/virtual-machine-rentals/terminate", s.authenticated(s.handleTerminateVM))
+	s.mux.HandleFunc("GET /v2/marketplace/bare-metal-rentals", s.authenticated(s.handleListBM))
+	s.mux.HandleFunc("POST /v2/marketplace/bare-metal-rentals", s.authenticated(s.handleRentBM))
+	s.mux.HandleFunc("POST /v2/marketplace/bare-metal-rentals/terminate", s.authenticated(s.handleTerminateBM))
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// SetNodes replaces the marketplace listing
+func (s *Server) SetNodes(nodes []hyperbolic.MarketplaceInstance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes = nodes
+}
+
+// SetBalance sets the account balance in cents
+func (s *Server) SetBalance(credits int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance.Credits = credits
+}
+
+func defaultNodes() []hyperbolic.MarketplaceInstance {
+	node := func(id, cluster, region, model string, gpuRAM, total, reserved, cents int) hyperbolic.MarketplaceInstance {
+		return hyperbolic.MarketplaceInstance{
+			ID:           id,
+			Status:       "node_ready",
+			ClusterName:  cluster,
+			GpusTotal:    total,
+			GpusReserved: reserved,
+			Location:     hyperbolic.Location{Region: region},
+			Pricing:      hyperbolic.Pricing{Price: hyperbolic.Price{Amount: cents, Period: "hourly", Agent: "platform"}},
+			Hardware: hyperbolic.Hardware{
+				CPUs:    []hyperbolic.CPU{{Model: "AMD EPYC 7763", VirtualCores: 16 * total}},
+				GPUs:    []hyperbolic.GPU{{Model: model, RAM: gpuRAM, Interface: "PCIeX16"}},
+				Storage: []hyperbolic.Storage{{Capacity: 500 * total}},
+				RAM:     []hyperbolic.RAM{{Capacity: 128 * total}},
+			},
+		}
+	}
+	return []hyperbolic.MarketplaceInstance{
+		node("mock-h100-1", "mock-cluster-east", "us-east", "NVIDIA-H100-80GB-HBM3", 81559, 8, 2, 149),
+		node("mock-4090-1", "mock-cluster-eu", "eu-central", "NVIDIA-GeForce-RTX-4090", 24564, 4, 0, 35),
+		node("mock-4090-2", "mock-cluster-eu", "eu-central", "NVIDIA-GeForce-RTX-4090", 24564, 4, 3, 39),
+		node("mock-a100-1", "mock-cluster-west", "us-west", "NVIDIA-A100-SXM4-80GB", 81920, 8, 8, 110),
+	}
+}
+
+// authenticated wraps handlers for endpoints that require a Bearer token
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || (s.APIKey != "" && token != s.APIKey) {
+			writeError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"detail": message})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// status reports the lifecycle status of a rental created at the given key
+func (s *Server) status(key string) string {
+	if time.Since(s.provisioned[key]) < s.ProvisionDelay {
+		return "starting"
+	}
+	return "running"
+}
+
+func (s *Server) handleMarketplace(w http.ResponseWriter, r *http.Request) {
+	var req hyperbolic.MarketplaceRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Filters are ignored: the CLI lists every node and filters them itself
+	nodes := append([]hyperbolic.MarketplaceInstance{}, s.nodes...)
+	writeJSON(w, http.StatusOK, hyperbolic.MarketplaceResponse{Instances: nodes})
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.user)
+}
+
+func (s *Server) handleBalance(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.balance)
+}
+
+func (s *Server) handleListSpot(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instances := make([]hyperbolic.UserInstance, len(s.spot))
+	for i, instance := range s.spot {
+		instance.Instance.Status = s.status(instance.ID)
+		instances[i] = instance
+	}
+	writeJSON(w, http.StatusOK, hyperbolic.InstancesResponse{Instances: instances})
+}
+
+func (s *Server) handleRentSpot(w http.ResponseWriter, r *http.Request) {
+	var request hyperbolic.RentRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nodeIndex := -1
+	for i, node := range s.nodes {
+		if node.ClusterName == request.ClusterName && node.ID == request.NodeName {
+			nodeIndex = i
+			break
+		}
+	}
+	if nodeIndex < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("node %s/%s not found", request.ClusterName, request.NodeName))
+		return
+	}
+	node := &s.nodes[nodeIndex]
+	if request.GpuCount < 1 || node.GpusTotal-node.GpusReserved < request.GpuCount {
+		writeError(w, http.StatusBadRequest, "not enough GPUs available on node")
+		return
+	}
+	cost := node.Pricing.Price.Amount * request.GpuCount
+	if s.balance.Credits < cost {
+		writeError(w, http.StatusPaymentRequired, "insufficient balance")
+		return
+	}
+	node.GpusReserved += request.GpuCount
+
+	s.nextID++
+	id := fmt.Sprintf("mock-%d", s.nextID)
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	var ports []hyperbolic.PortMapping
+	if request.Image != nil {
+		for _, port := range request.Image.Ports {
+			ports = append(ports, hyperbolic.PortMapping{Domain: id + ".mock.hyperbolic.xyz", Protocol: "https", Port: port})
+		}
+	}
+
+	gpus := make([]hyperbolic.UserInstanceGPU, request.GpuCount)
+	for i := range gpus {
+		gpus[i] = hyperbolic.UserInstanceGPU{Model: node.Hardware.GPUs[0].Model, RAM: node.Hardware.GPUs[0].RAM}
+	}
+
+	s.spot = append(s.spot, hyperbolic.UserInstance{
+		ID:           id,
+		Start:        now,
+		Created:      now,
+		SSHCommand:   fmt.Sprintf("ssh ubuntu@%s.mock.hyperbolic.xyz -p %d", node.ID, 31000+s.nextID%1000),
+		PortMappings: ports,
+		Instance: hyperbolic.UserInstanceDetails{
+			ID:       node.ID,
+			Hardware: hyperbolic.UserInstanceHardware{GPUs: gpus},
+			Pricing: hyperbolic.UserInstancePricing{Price: hyperbolic.UserInstancePrice{
+				Amount: float64(node.Pricing.Price.Amount),
+				Period: "hourly",
+			}},
+			GPUCount: request.GpuCount,
+		},
+	})
+	s.spotNodes[id] = nodeIndex
+	s.provisioned[id] = time.Now()
+
+	writeJSON(w, http.StatusOK, hyperbolic.SpotRentResponse{InstanceID: id, Status: "success", Message: "instance requested"})
+}
+
+func (s *Server) handleTerminateSpot(w http.ResponseWriter, r *http.Request) {
+	var request hyperbolic.TerminateRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, instance := range s.spot {
+		if instance.ID == request.ID {
+			if nodeIndex, ok := s.spotNodes[instance.ID]; ok && nodeIndex < len(s.nodes) {
+				s.nodes[nodeIndex].GpusReserved -= instance.Instance.GPUCount
+			}
+			s.spot = append(s.spot[:i], s.spot[i+1:]...)
+			delete(s.spotNodes, instance.ID)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("instance %s not found", request.ID))
+}
+
+func (s *Server) handleVMOptions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.vmOptions)
+}
+
+func (s *Server) handleBMOptions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.bmOptions)
+}
+
+func (s *Server) listOnDemand(w http.ResponseWriter, rentals []hyperbolic.OnDemandInstance) {
+	instances := make([]hyperbolic.OnDemandInstance, len(rentals))
+	for i, instance := range rentals {
+		instance.Status = s.status(strconv.Itoa(instance.ID))
+		instances[i] = instance
+	}
+	writeJSON(w, http.StatusOK, instances)
+}
+
+func (s *Server) handleListVMs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listOnDemand(w, s.vms)
+}
+
+func (s *Server) handleListBM(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listOnDemand(w, s.bareMetal)
+}
+
+// newOnDemand allocates an ID and the common fields of an on-demand rental
+func (s *Server) newOnDemand(rentalType string, costPerHour int) hyperbolic.OnDemandInstance {
+	s.nextID++
+	now := time.Now().UTC().Format("2006-01-02 15:04:05+00")
+	s.provisioned[strconv.Itoa(s.nextID)] = time.Now()
+	return hyperbolic.OnDemandInstance{
+		ID:             s.nextID,
+		CreatedAt:      now,
+		StartedAt:      now,
+		UserID:         s.user.ID,
+		ExternalID:     fmt.Sprintf("mock-ext-%d", s.nextID),
+		RentalProvider: "mock",
+		CostPerHour:    costPerHour,
+		Meta: hyperbolic.OnDemandInstanceMeta{
+			Name:       fmt.Sprintf("mock-%s-%d", rentalType, s.nextID),
+			RentalType: rentalType,
+		},
+	}
+}
+
+func rentResponse(instance hyperbolic.OnDemandInstance) hyperbolic.OnDemandRentResponse {
+	var response hyperbolic.OnDemandRentResponse
+	response.ID = instance.ID
+	response.ExternalID = instance.ExternalID
+	response.CostPerHour = instance.CostPerHour
+	response.Meta.Name = instance.Meta.Name
+	response.Meta.GPUCount = instance.Meta.GPUCount
+	response.Meta.RentalType = instance.Meta.RentalType
+	response.Meta.NetworkType = instance.Meta.NetworkType
+	return response
+}
+
+func (s *Server) handleRentVM(w http.ResponseWriter, r *http.Request) {
+	var request hyperbolic.VirtualMachineRentalRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	gpuCount, err := strconv.Atoi(request.GPUCount)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "gpuCount must be a number")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var option *hyperbolic.VirtualMachineOption
+	for i := range s.vmOptions {
+		if s.vmOptions[i].GPUCount == gpuCount {
+			option = &s.vmOptions[i]
+		}
+	}
+	if option == nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("no virtual machine configuration with %d GPUs", gpuCount))
+		return
+	}
+
+	cost := int(option.CostPerHour*100+0.5) * gpuCount
+	if s.balance.Credits < cost {
+		writeError(w, http.StatusPaymentRequired, "insufficient balance")
+		return
+	}
+
+	instance := s.newOnDemand("virtual-machine", cost)
+	publicIP := fmt.Sprintf("198.51.100.%d", instance.ID%250+1)
+	instance.Meta.GPUCount = gpuCount
+	instance.Meta.PublicIP = publicIP
+	instance.Meta.InternalIP = fmt.Sprintf("10.0.0.%d", instance.ID%250+1)
+	instance.Meta.SSHCommand = fmt.Sprintf("ssh ubuntu@%s -p 22", publicIP)
+	instance.Meta.OperatingSystem = "Ubuntu 22.04"
+	instance.Meta.Resources = &hyperbolic.OnDemandInstanceResources{
+		RAMGb:     180 * gpuCount,
+		StorageGb: 1000 * gpuCount,
+		VCPUCount: 24 * gpuCount,
+		GPUs:      map[string]hyperbolic.OnDemandGPU{"NVIDIA-H100-80GB-HBM3": {Count: gpuCount}},
+	}
+	s.vms = append(s.vms, instance)
+
+	writeJSON(w, http.StatusOK, rentResponse(instance))
+}
+
+func (s *Server) handleRentBM(w http.ResponseWriter, r *http.Request) {
+	var request hyperbolic.BareMetalRentalRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var option hyperbolic.BareMetalNetworkOption
+	switch request.NetworkType {
+	case "ethernet":
+		option = s.bmOptions.Ethernet
+	case "infiniband":
+		option = s.bmOptions.Infiniband
+	default:
+		writeError(w, http.StatusBadRequest, "networkType must be 'ethernet' or 'infiniband'")
+		return
+	}
+	if request.GPUCount < 8 || request.GPUCount%8 != 0 || request.GPUCount > option.GPUCount {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("gpuCount must be a multiple of 8 between 8 and %d", option.GPUCount))
+		return
+	}
+
+	cost := int(option.CostPerHour*100+0.5) * request.GPUCount
+	if s.balance.Credits < cost {
+		writeError(w, http.StatusPaymentRequired, "insufficient balance")
+		return
+	}
+
+	instance := s.newOnDemand("bare-metal", cost)
+	nodeCount := request.GPUCount / 8
+	instance.Meta.GPUCount = request.GPUCount
+	instance.Meta.NodeCount = nodeCount
+	instance.Meta.NetworkType = request.NetworkType
+	instance.Meta.Username = "ubuntu"
+	instance.Meta.OperatingSystem = "Ubuntu 22.04"
+	instance.Meta.SpecsPerNode = &hyperbolic.OnDemandInstanceSpecs{
+		RAMGb:     2048,
+		CPUCount:  192,
+		CPUModel:  "Intel Xeon Platinum 8480+",
+		GPUCount:  8,
+		GPUModel:  "NVIDIA-H100-80GB-HBM3",
+		StorageGb: 30000,
+	}
+	for i := 0; i < nodeCount; i++ {
+		instance.Meta.NodeNetworking = append(instance.Meta.NodeNetworking, hyperbolic.OnDemandNetworking{
+			PublicIP:  fmt.Sprintf("203.0.113.%d", (instance.ID+i)%250+1),
+			PrivateIP: fmt.Sprintf("10.10.0.%d", i+1),
+		})
+	}
+	s.bareMetal = append(s.bareMetal, instance)
+
+	writeJSON(w, http.StatusOK, rentResponse(instance))
+}
+
+func (s *Server) terminateOnDemand(w http.ResponseWriter, r *http.Request, rentals *[]hyperbolic.OnDemandInstance) {
+	var request hyperbolic.OnDemandTerminateRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, instance := range *rentals {
+		if instance.ID == request.RentalID {
+			*rentals = append((*rentals)[:i], (*rentals)[i+1:]...)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("rental %d not found", request.RentalID))
+}
+
+func (s *Server) handleTerminateVM(w http.ResponseWriter, r *http.Request) {
+	s.terminateOnDemand(w, r, &s.vms)
+}
+
+func (s *Server) handleTerminateBM(w http.ResponseWriter, r *http.Request) {
+	s.terminateOnDemand(w, r, &s.bareMetal)
+}
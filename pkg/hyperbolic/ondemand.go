@@ -0,0 +1,44 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"context"
+	"net/http"
+)
+
+// VirtualMachineOption represents a VM option from the API
+type VirtualMachineOption struct {
+	GPUCount    int     `json:"gpuCount"`
+	CostPerHour float64 `json:"costPerHour"`
+}
+
+// VirtualMachineOptions represents the response from /v2/marketplace/virtual-machine-options
+type VirtualMachineOptions []VirtualMachineOption
+
+// BareMetalNetworkOption represents network configuration for bare metal instances
+type BareMetalNetworkOption struct {
+	GPUCount    int     `json:"gpuCount"`
+	CostPerHour float64 `json:"costPerHour"`
+}
+
+// BareMetalOptions represents the response from /v2/marketplace/bare-metal-options
+type BareMetalOptions struct {
+	Ethernet   BareMetalNetworkOption `json:"ethernet"`
+	Infiniband BareMetalNetworkOption `json:"infiniband"`
+}
+
+// GetVirtualMachineOptions returns the available on-demand VM configurations
+func (c *Client) GetVirtualMachineOptions(ctx context.Context) (VirtualMachineOptions, error) {
+	var options VirtualMachineOptions
+	err := c.do(ctx, http.MethodGet, "/v2/marketplace/virtual-machine-options", nil, &options)
+	return options, err
+}
+
+// GetBareMetalOptions returns the available on-demand bare-metal configurations
+func (c *Client) GetBareMetalOptions(ctx context.Context) (BareMetalOptions, error) {
+	var options BareMetalOptions
+	err := c.do(ctx, http.MethodGet, "/v2/marketplace/bare-metal-options", nil, &options)
+	return options, err
+}
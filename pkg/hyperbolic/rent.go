@@ -0,0 +1,131 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Config IDs for the on-demand H100 configurations
+const (
+	VirtualMachineConfigID = "c6fd6253-cbb6-4ea8-a20c-47644b431f1c"
+	BareMetalConfigID      = "a3111bd4-550a-47d0-838a-0a52bff2ae3f"
+)
+
+// SpotImage is the default container image used when exposing ports on a spot rental
+const SpotImage = "ghcr.io/hyperboliclabs/hyper-dos/sshbox"
+
+type RentRequest struct {
+	ClusterName string `json:"cluster_name"`
+	NodeName    string `json:"node_name"`
+	GpuCount    int    `json:"gpu_count"`
+	Image       *Image `json:"image,omitempty"`
+}
+
+type Image struct {
+	Name  string `json:"name"`
+	Ports []int  `json:"ports,omitempty"`
+}
+
+// OnDemand request structures
+type VirtualMachineRentalRequest struct {
+	ConfigID string `json:"configId"`
+	GPUCount string `json:"gpuCount"`
+}
+
+type BareMetalRentalRequest struct {
+	ConfigID    string `json:"configId"`
+	NetworkType string `json:"networkType"`
+	GPUCount    int    `json:"gpuCount"`
+}
+
+// Response structures
+type SpotRentResponse struct {
+	InstanceID string `json:"instance_id"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+}
+
+type OnDemandRentResponse struct {
+	ID          int    `json:"id"`
+	ExternalID  string `json:"externalId"`
+	CostPerHour int    `json:"costPerHour"`
+	Meta        struct {
+		Name        string `json:"name"`
+		GPUCount    int    `json:"gpu_count"`
+		RentalType  string `json:"rental_type"`
+		NetworkType string `json:"network_type"`
+	} `json:"meta"`
+}
+
+type TerminateRequest struct {
+	ID string `json:"id"`
+}
+
+type OnDemandTerminateRequest struct {
+	RentalID int `json:"rentalId"`
+}
+
+// RentSpotInstance rents GPUs on a spot marketplace node. The create endpoint
+// does not always return a parseable body, so the returned response may be
+// zero-valued even when the rental succeeded.
+func (c *Client) RentSpotInstance(ctx context.Context, request RentRequest) (SpotRentResponse, error) {
+	var response SpotRentResponse
+	body, err := c.doRaw(ctx, http.MethodPost, "/v1/marketplace/instances/create", request, false)
+	if err != nil {
+		return response, err
+	}
+	_ = json.Unmarshal(body, &response)
+	return response, nil
+}
+
+// RentVirtualMachine rents an on-demand virtual machine with gpuCount GPUs.
+// As with RentSpotInstance, the response may be zero-valued on success.
+func (c *Client) RentVirtualMachine(ctx context.Context, gpuCount int) (OnDemandRentResponse, error) {
+	request := VirtualMachineRentalRequest{
+		ConfigID: VirtualMachineConfigID,
+		GPUCount: strconv.Itoa(gpuCount),
+	}
+	return c.rentOnDemand(ctx, "/v2/marketplace/virtual-machine-rentals", request)
+}
+
+// RentBareMetal rents on-demand bare-metal nodes with gpuCount GPUs in total
+// on an "ethernet" or "infiniband" network.
+// As with RentSpotInstance, the response may be zero-valued on success.
+func (c *Client) RentBareMetal(ctx context.Context, gpuCount int, networkType string) (OnDemandRentResponse, error) {
+	request := BareMetalRentalRequest{
+		ConfigID:    BareMetalConfigID,
+		NetworkType: networkType,
+		GPUCount:    gpuCount,
+	}
+	return c.rentOnDemand(ctx, "/v2/marketplace/bare-metal-rentals", request)
+}
+
+func (c *Client) rentOnDemand(ctx context.Context, path string, request interface{}) (OnDemandRentResponse, error) {
+	var response OnDemandRentResponse
+	body, err := c.doRaw(ctx, http.MethodPost, path, request, false)
+	if err != nil {
+		return response, err
+	}
+	_ = json.Unmarshal(body, &response)
+	return response, nil
+}
+
+// TerminateSpotInstance terminates a spot rental
+func (c *Client) TerminateSpotInstance(ctx context.Context, instanceID string) error {
+	return c.do(ctx, http.MethodPost, "/v1/marketplace/instances/terminate", TerminateRequest{ID: instanceID}, nil)
+}
+
+// TerminateVirtualMachineRental terminates an on-demand virtual machine rental
+func (c *Client) TerminateVirtualMachineRental(ctx context.Context, rentalID int) error {
+	return c.do(ctx, http.MethodPost, "/v2/marketplace/virtual-machine-rentals/terminate", OnDemandTerminateRequest{RentalID: rentalID}, nil)
+}
+
+// TerminateBareMetalRental terminates an on-demand bare-metal rental
+func (c *Client) TerminateBareMetalRental(ctx context.Context, rentalID int) error {
+	return c.do(ctx, http.MethodPost, "/v2/marketplace/bare-metal-rentals/terminate", OnDemandTerminateRequest{RentalID: rentalID}, nil)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults used by NewClient
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 3
+)
+
+// maxRetryAfter bounds how long a Retry-After header can make us wait
+const maxRetryAfter = time.Minute
+
+// RetryPolicy controls how failed requests are retried.
+//
+// Idempotent requests (reads) are retried on network errors, 429 and 5xx
+// responses. Requests that create or terminate rentals are only retried on
+// 429, which the API returns before doing any work.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles each attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential backoff
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+	}
+}
+
+// WithRetryPolicy overrides the retry policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.Retry = policy
+	}
+}
+
+// WithTimeout replaces the HTTP client with one from NewHTTPClient(timeout)
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.HTTPClient = NewHTTPClient(timeout)
+	}
+}
+
+// NewHTTPClient returns an HTTP client whose requests, including reading the
+// response body, give up after timeout. A zero timeout disables the overall
+// limit but keeps the connect and TLS handshake timeouts.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.ResponseHeaderTimeout = timeout
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying
+func shouldRetry(err error, idempotent bool) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+		return idempotent && apiErr.IsServerError() && apiErr.StatusCode != http.StatusNotImplemented
+	}
+	var networkErr *NetworkError
+	return idempotent && errors.As(err, &networkErr)
+}
+
+// backoff returns the delay before retry number attempt (starting at 0),
+// using exponential backoff with jitter and honouring Retry-After.
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	// Equal jitter: wait at least half the backoff so retries still spread out
+	if delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	}
+	if delay < 0 {
+		return 0
+	}
+	if delay > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return delay
+}
@@ -0,0 +1,258 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic"
+	"github.com/HyperbolicLabs/hyperbolic-cli/pkg/hyperbolic/mock"
+)
+
+// hangUp is a failure status that closes the connection without responding,
+// which the client sees as a network error
+const hangUp = -1
+
+// flakyServer serves the mock API, but fails the first requests to one
+// endpoint and counts every request made to it
+type flakyServer struct {
+	route      string
+	failures   []int
+	retryAfter string
+
+	mu       sync.Mutex
+	requests int
+}
+
+func (f *flakyServer) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method+" "+r.URL.Path != f.route {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		f.mu.Lock()
+		attempt := f.requests
+		f.requests++
+		f.mu.Unlock()
+
+		if attempt >= len(f.failures) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		status := f.failures[attempt]
+		if status == hangUp {
+			conn, _, err := http.NewResponseController(w).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		http.Error(w, `{"detail":"injected failure"}`, status)
+	})
+}
+
+func (f *flakyServer) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+// newFlakyClient starts the mock API behind f and returns a client for it
+// that retries without noticeable delay
+func newFlakyClient(t *testing.T, f *flakyServer) *hyperbolic.Client {
+	t.Helper()
+	server := httptest.NewServer(f.wrap(mock.NewServer()))
+	t.Cleanup(server.Close)
+	return hyperbolic.NewClient("test-key",
+		hyperbolic.WithBaseURL(server.URL),
+		hyperbolic.WithRetryPolicy(hyperbolic.RetryPolicy{
+			MaxRetries: 3,
+			BaseDelay:  time.Millisecond,
+			MaxDelay:   5 * time.Millisecond,
+		}),
+	)
+}
+
+func TestRetryReads(t *testing.T) {
+	reads := []struct {
+		name  string
+		route string
+		call  func(context.Context, *hyperbolic.Client) error
+	}{
+		{"list instances", "GET /v1/marketplace/instances", func(ctx context.Context, c *hyperbolic.Client) error {
+			_, err := c.ListSpotInstances(ctx)
+			return err
+		}},
+		{"balance", "GET /billing/get_current_balance", func(ctx context.Context, c *hyperbolic.Client) error {
+			_, err := c.GetBalance(ctx)
+			return err
+		}},
+		// The marketplace listing is a POST but only reads
+		{"marketplace", "POST /v1/marketplace", func(ctx context.Context, c *hyperbolic.Client) error {
+			_, err := c.ListMarketplace(ctx, nil)
+			return err
+		}},
+	}
+	failures := []struct {
+		name   string
+		status int
+	}{
+		{"server error", http.StatusBadGateway},
+		{"rate limited", http.StatusTooManyRequests},
+		{"network error", hangUp},
+	}
+
+	for _, read := range reads {
+		for _, failure := range failures {
+			t.Run(read.name+"/"+failure.name, func(t *testing.T) {
+				f := &flakyServer{route: read.route, failures: []int{failure.status, failure.status}}
+				client := newFlakyClient(t, f)
+
+				if err := read.call(context.Background(), client); err != nil {
+					t.Fatalf("expected success after retries, got %v", err)
+				}
+				if got := f.count(); got != 3 {
+					t.Errorf("expected 3 requests, got %d", got)
+				}
+			})
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	f := &flakyServer{route: "GET /billing/get_current_balance", failures: []int{500, 500, 500, 500, 500}}
+	client := newFlakyClient(t, f)
+
+	_, err := client.GetBalance(context.Background())
+	var apiErr *hyperbolic.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+		t.Fatalf("expected the last 500 to be returned, got %v", err)
+	}
+	if got := f.count(); got != 4 {
+		t.Errorf("expected the first attempt and 3 retries, got %d requests", got)
+	}
+}
+
+func TestRetryNotImplemented(t *testing.T) {
+	f := &flakyServer{route: "GET /billing/get_current_balance", failures: []int{http.StatusNotImplemented}}
+	client := newFlakyClient(t, f)
+
+	if _, err := client.GetBalance(context.Background()); err == nil {
+		t.Fatal("expected the 501 to be returned")
+	}
+	if got := f.count(); got != 1 {
+		t.Errorf("expected a 501 not to be retried, got %d requests", got)
+	}
+}
+
+func TestRetryCreates(t *testing.T) {
+	request := hyperbolic.RentRequest{ClusterName: "mock-cluster-eu", NodeName: "mock-4090-1", GpuCount: 1}
+	creates := []struct {
+		name  string
+		route string
+		call  func(context.Context, *hyperbolic.Client) error
+	}{
+		{"spot", "POST /v1/marketplace/instances/create", func(ctx context.Context, c *hyperbolic.Client) error {
+			_, err := c.RentSpotInstance(ctx, request)
+			return err
+		}},
+		{"virtual machine", "POST /v2/marketplace/virtual-machine-rentals", func(ctx context.Context, c *hyperbolic.Client) error {
+			_, err := c.RentVirtualMachine(ctx, 1)
+			return err
+		}},
+		{"terminate", "POST /v1/marketplace/instances/terminate", func(ctx context.Context, c *hyperbolic.Client) error {
+			return c.TerminateSpotInstance(ctx, "mock-1")
+		}},
+	}
+
+	for _, create := range creates {
+		// A create that failed on the server may still have been made, so
+		// retrying it could rent twice
+		for _, failure := range []struct {
+			name   string
+			status int
+		}{
+			{"500", http.StatusInternalServerError},
+			{"502", http.StatusBadGateway},
+			{"503", http.StatusServiceUnavailable},
+			{"network error", hangUp},
+		} {
+			t.Run(create.name+"/"+failure.name, func(t *testing.T) {
+				f := &flakyServer{route: create.route, failures: []int{failure.status}}
+				client := newFlakyClient(t, f)
+
+				if err := create.call(context.Background(), client); err == nil {
+					t.Fatal("expected the failure to be returned")
+				}
+				if got := f.count(); got != 1 {
+					t.Errorf("expected exactly 1 request, got %d", got)
+				}
+			})
+		}
+
+		// 429 is returned before any work is done
+		t.Run(create.name+"/rate limited", func(t *testing.T) {
+			f := &flakyServer{route: create.route, failures: []int{http.StatusTooManyRequests}}
+			client := newFlakyClient(t, f)
+
+			err := create.call(context.Background(), client)
+			var apiErr *hyperbolic.APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+				t.Fatalf("expected the 429 to be retried, got %v", err)
+			}
+			if got := f.count(); got != 2 {
+				t.Errorf("expected 2 requests, got %d", got)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	f := &flakyServer{
+		route:      "POST /v1/marketplace/instances/create",
+		failures:   []int{http.StatusTooManyRequests},
+		retryAfter: "1",
+	}
+	client := newFlakyClient(t, f)
+
+	start := time.Now()
+	_, err := client.RentSpotInstance(context.Background(), hyperbolic.RentRequest{ClusterName: "mock-cluster-eu", NodeName: "mock-4090-1", GpuCount: 1})
+	if err != nil {
+		t.Fatalf("expected success after the retry, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the retry to wait for Retry-After (1s), waited %s", elapsed)
+	}
+	if got := f.count(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestRetryStopsWhenCancelled(t *testing.T) {
+	f := &flakyServer{route: "GET /billing/get_current_balance", failures: []int{503}, retryAfter: "30"}
+	client := newFlakyClient(t, f)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetBalance(ctx); err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the wait for Retry-After to stop when the context ended, waited %s", elapsed)
+	}
+	if got := f.count(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Hyperbolic Labs
+*/
+package hyperbolic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPollInterval is how often WaitForInstance and WaitForSSH check
+// on a rental
+const DefaultPollInterval = 5 * time.Second
+
+// IsRunning reports whether the instance has finished provisioning
+func (i Instance) IsRunning() bool {
+	return strings.EqualFold(i.Status, "running")
+}
+
+// HasEnded reports whether the instance has stopped or failed, so it will
+// never become running
+func (i Instance) HasEnded() bool {
+	switch strings.ToLower(i.Status) {
+	case "terminated", "terminating", "stopped", "failed", "error", "cancelled", "canceled":
+		return true
+	}
+	return !i.EndedAt.IsZero()
+}
+
+// InstanceEndedError is returned by WaitForInstance when the rental stops
+// or fails before it is running
+type InstanceEndedError struct {
+	Instance Instance
+}
+
+func (e *InstanceEndedError) Error() string {
+	return fmt.Sprintf("instance %s ended with status '%s' before it was running", e.Instance.ID, e.Instance.Status)
+}
+
+// WaitForInstance polls the rental with the given ID every interval until it
+// is running, and returns it. progress, if not nil, is called with the
+// result of each poll.
+//
+// A new rental can take a moment to be listed, so ErrInstanceNotFound does
+// not end the wait. If ctx is done first, the last instance seen is returned
+// with ctx's error.
+func (c *Client) WaitForInstance(ctx context.Context, id string, interval time.Duration, progress func(Instance)) (Instance, error) {
+	var last Instance
+	for {
+		instance, err := c.GetInstance(ctx, id)
+		switch {
+		case err == nil:
+			last = instance
+			if progress != nil {
+				progress(instance)
+			}
+			if instance.IsRunning() {
+				return instance, nil
+			}
+			if instance.HasEnded() {
+				return instance, &InstanceEndedError{Instance: instance}
+			}
+		case ctx.Err() != nil:
+			return last, ctx.Err()
+		case !errors.Is(err, ErrInstanceNotFound):
+			return last, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// WaitForSSH waits until endpoint accepts TCP connections, trying every
+// interval until ctx is done
+func WaitForSSH(ctx context.Context, endpoint SSHEndpoint, interval time.Duration) error {
+	address := net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+	var dialer net.Dialer
+	for {
+		dialCtx, cancel := context.WithTimeout(ctx, interval)
+		conn, err := dialer.DialContext(dialCtx, "tcp", address)
+		cancel()
+		if err == nil {
+			return conn.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("SSH port %s not reachable: %w", address, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}